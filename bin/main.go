@@ -2,58 +2,378 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/DLC-link/cantcost/internal/aggregator"
+	"github.com/DLC-link/cantcost/internal/anomaly"
+	"github.com/DLC-link/cantcost/internal/api"
+	"github.com/DLC-link/cantcost/internal/attribution"
+	"github.com/DLC-link/cantcost/internal/budget"
 	"github.com/DLC-link/cantcost/internal/catcher"
-	"github.com/DLC-link/cantcost/internal/env"
+	"github.com/DLC-link/cantcost/internal/config"
+	"github.com/DLC-link/cantcost/internal/correlation"
+	"github.com/DLC-link/cantcost/internal/dashboard"
+	"github.com/DLC-link/cantcost/internal/deadletter"
 	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/health"
+	"github.com/DLC-link/cantcost/internal/httpclient"
+	"github.com/DLC-link/cantcost/internal/metrics"
 	"github.com/DLC-link/cantcost/internal/parser"
+	"github.com/DLC-link/cantcost/internal/pricing"
+	"github.com/DLC-link/cantcost/internal/rollup"
+	"github.com/DLC-link/cantcost/internal/store"
+	"github.com/DLC-link/cantcost/internal/stream"
+	"github.com/DLC-link/cantcost/internal/version"
+	"github.com/DLC-link/cantcost/pkg/signing"
 	slogcontext "github.com/PumpkinSeed/slog-context"
 )
 
 func main() {
-	slog.SetDefault(
-		slog.New(
-			slogcontext.NewHandler(
-				slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-					Level: env.GetLogLevel(),
-				}),
-			),
-		),
-	)
-	env.Print()
+	cfg, err := config.Load(os.Getenv(config.FileEnv))
+	if err != nil {
+		setupLogger(slog.LevelInfo)
+		slog.Error("Failed to load configuration", slog.Any("error", err))
+		os.Exit(1)
+	}
+	setupLogger(cfg.Level())
+	parser.Configure(parser.Options{
+		IncludeMessage: cfg.IncludeMessage,
+		ValidationMode: cfg.Validation.Mode,
+	})
 
-	var exporter = exporters.New()
-	if env.GetExporterType() == "http" {
-		httpExporter := exporters.NewHTTPExporter(
-			env.GetHTTPExporterURL(),
-			env.GetHTTPExporterAuthHeader(),
-			env.GetHTTPExporterBatchSize(),
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(replay(cfg, os.Args[2:]))
+	}
+
+	slog.Info("Starting cantcost", slog.String("version", version.Version))
+	slog.Info("Configuration", slog.Any("config", cfg.Redacted()))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := api.New(cfg.API.Addr)
+
+	selfMetrics := metrics.New(cfg.Metrics.SummaryInterval.Std())
+	server.Handle("GET /metrics", selfMetrics.Handler())
+
+	outputs, err := newExporter(cfg.Exporters)
+	if err != nil {
+		slog.Error("Invalid exporter configuration", slog.Any("error", err))
+		os.Exit(1)
+	}
+	outputs.Observe(selfMetrics)
+	for name, queue := range outputs.Queues() {
+		selfMetrics.Queue("exporter/"+name, queue.QueueDepth)
+	}
+	server.Handle("GET /v1/exporters", api.ExportersHandler(outputs))
+	targets := make([]string, len(cfg.Targets))
+	for i, target := range cfg.Targets {
+		targets[i] = target.String()
+	}
+	monitor := health.New(cfg.Health.StaleAfter.Std(), targets...)
+	server.Handle("GET /healthz", api.AliveHandler())
+	server.Handle("GET /livez", api.LivenessHandler(monitor))
+	server.Handle("GET /readyz", api.ReadinessHandler(monitor, outputs))
+	// The live stream and the dashboard show the records of the aggregation
+	// stages too, so they are sinks
+	broker := stream.New(cfg.Stream.BufferSize)
+	outputs.AddExporter(broker)
+	server.Handle("GET /v1/stream", api.StreamHandler(broker))
+	server.Handle("GET /v1/stream/ws", api.WebSocketHandler(broker))
+	if cfg.Dashboard.Enabled {
+		dash := dashboard.New(cfg.Dashboard.History.Std(), cfg.Dashboard.Refresh.Std(), broker)
+		dash.MaxTraces = cfg.Dashboard.MaxTraces
+		outputs.AddExporter(dash)
+		server.Handle("GET /dashboard/", http.StripPrefix("/dashboard", dash.Handler()))
+		slog.Info("Dashboard configured", slog.String("path", "/dashboard/"))
+	}
+	var sinks exporters.Exporter = outputs
+	rates, err := newPricingRates(cfg.Pricing)
+	if err != nil {
+		slog.Error("Invalid pricing configuration", slog.Any("error", err))
+		os.Exit(1)
+	}
+	if rates != nil {
+		// Enrich the events and the records of the aggregation stages alike
+		sinks = pricing.NewEnricher(rates, sinks)
+		slog.Info("Pricing configured", slog.Any("rates", rates))
+	}
+	if cfg.Correlation.Enabled {
+		correlator := correlation.New(cfg.Correlation.Wait.Std(), cfg.Correlation.Retention.Std(), sinks)
+		sinks = correlator
+		selfMetrics.Queue("correlation", correlator.QueueDepth)
+		server.Handle("POST /v1/submissions", api.SubmissionsHandler(correlator, cfg.Correlation.AuthHeader))
+		slog.Info("Submission correlation configured",
+			slog.Duration("wait", cfg.Correlation.Wait.Std()),
+			slog.Duration("retention", cfg.Correlation.Retention.Std()),
+		)
+	}
+	exporter := exporters.New(sinks)
+	if window := cfg.TraceAggregation.Window.Std(); window > 0 {
+		traceAggregator := aggregator.NewTrace(window, sinks)
+		exporter.AddExporter(traceAggregator)
+		slog.Info("Trace aggregation configured", slog.Duration("window", window))
+	}
+	if cfg.Attribution.Policy != "" {
+		// The configuration is validated already
+		policy, _ := attribution.ParsePolicy(cfg.Attribution.Policy)
+		attributor := attribution.New(policy, cfg.Attribution.Window.Std(), cfg.Attribution.Retention.Std(), sinks)
+		attributor.AllowedLateness = cfg.Attribution.AllowedLateness.Std()
+		exporter.AddExporter(attributor)
+		server.Handle("GET /v1/attribution", api.AttributionHandler(attributor))
+		slog.Info("Cost attribution configured",
+			slog.String("policy", string(policy)),
+			slog.Duration("window", cfg.Attribution.Window.Std()),
+			slog.Duration("allowed_lateness", cfg.Attribution.AllowedLateness.Std()),
+		)
+	}
+	if windows := config.StdDurations(cfg.Rollup.Windows); len(windows) > 0 {
+		rollups := rollup.New(windows, cfg.Rollup.AllowedLateness.Std(), sinks)
+		exporter.AddExporter(rollups)
+		slog.Info("Rollups configured",
+			slog.Any("windows", windows),
+			slog.Duration("allowed_lateness", cfg.Rollup.AllowedLateness.Std()),
+		)
+	}
+	if cfg.Budget.Limit > 0 || len(cfg.Budget.ParticipantLimits) > 0 {
+		var notifier budget.Notifier
+		if cfg.Budget.WebhookURL != "" {
+			webhook := budget.NewWebhook(cfg.Budget.WebhookURL, cfg.Budget.WebhookAuthHeader)
+			client, err := httpclient.New(cfg.Budget.WebhookTLS, config.OAuth2{})
+			if err != nil {
+				slog.Error("Invalid budget webhook configuration", slog.Any("error", err))
+				os.Exit(1)
+			}
+			// The client may be http.DefaultClient, which must not get the timeout
+			webhook.Client = &http.Client{Transport: client.Transport, Timeout: cfg.Budget.WebhookTimeout.Std()}
+			notifier = webhook
+		}
+		tracker := budget.New(
+			cfg.Budget.Period.Std(),
+			cfg.Budget.Limit,
+			cfg.Budget.ParticipantLimits,
+			cfg.Budget.AlertThresholds,
+			notifier,
+		)
+		tracker.NotifyTimeout = cfg.Budget.WebhookTimeout.Std()
+		exporter.AddExporter(tracker)
+		server.Handle("GET /v1/budget", api.BudgetHandler(tracker))
+		slog.Info("Traffic budget configured",
+			slog.Duration("period", cfg.Budget.Period.Std()),
+			slog.Int("limit", cfg.Budget.Limit),
+			slog.Any("thresholds", cfg.Budget.AlertThresholds),
 		)
-		exporter.AddExporter(httpExporter)
-		slog.Info("HTTP exporter configured",
-			slog.String("url", env.GetHTTPExporterURL()),
+	}
+	if cfg.Anomaly.Method != "" {
+		// The configuration is validated already
+		method, _ := anomaly.ParseMethod(cfg.Anomaly.Method)
+		key, _ := anomaly.ParseKey(cfg.Anomaly.Key)
+		detector := anomaly.New(
+			method,
+			key,
+			cfg.Anomaly.Threshold,
+			cfg.Anomaly.Warmup,
+			cfg.Anomaly.EWMAAlpha,
+			sinks,
+		)
+		detector.MaxKeys = cfg.Anomaly.MaxKeys
+		exporter.AddExporter(detector)
+		slog.Info("Anomaly detection configured",
+			slog.String("method", string(method)),
+			slog.String("key", string(key)),
+			slog.Float64("threshold", cfg.Anomaly.Threshold),
+			slog.Int("max_keys", cfg.Anomaly.MaxKeys),
 		)
 	}
+	if capacity := cfg.Store.Capacity; capacity > 0 {
+		recent := store.New(capacity, cfg.Store.TTL.Std())
+		exporter.AddExporter(recent)
+		server.Handle("GET /v1/traces/{traceID}", api.TraceHandler(recent))
+		server.Handle("GET /v1/events", api.EventsHandler(recent))
+		server.Handle("GET /v1/totals", api.TotalsHandler(recent))
+		slog.Info("Query store configured",
+			slog.Int("capacity", capacity),
+			slog.Duration("ttl", cfg.Store.TTL.Std()),
+		)
+	}
+	deadLetter := newDeadLetterSink(cfg.DeadLetter)
+	if err := exporter.Start(ctx); err != nil {
+		slog.Error("Failed to start exporters", slog.Any("error", err))
+		os.Exit(1)
+	}
 
-	ctx := context.Background()
-	err := catcher.Stream(ctx, func(ctx context.Context, line string) error {
-		if strings.Contains(strings.ToLower(line), "eventcost") {
-			parsedLine, err := parser.ProcessLine(line)
+	go selfMetrics.Run(ctx)
+	go func() {
+		if err := server.Run(ctx); err != nil {
+			slog.ErrorContext(ctx, "HTTP server failed", slog.Any("error", err))
+		}
+	}()
+
+	handleLine := func(ctx context.Context, line []byte) error {
+		if parser.Relevant(line) {
+			selfMetrics.LineMatched()
+			parsedLine, err := parser.ProcessBytes(line)
 			if err != nil {
+				selfMetrics.ParseFailed(err)
 				slog.ErrorContext(ctx, "Failed to parse log line", slog.Any("error", err))
+				quarantine(ctx, deadLetter, string(line), err)
 				return err
 			}
+			if parsedLine.Kind == parser.KindTrafficRejection {
+				slog.WarnContext(ctx, "Submission rejected for insufficient traffic",
+					slog.String("trace_id", parsedLine.TraceID),
+					slog.String("code", parsedLine.TrafficRejection.Code),
+					slog.Int("required", parsedLine.TrafficRejection.Required),
+					slog.Int("available", parsedLine.TrafficRejection.Available),
+				)
+			}
 			if err := exporter.Export(ctx, &parsedLine); err != nil {
 				slog.ErrorContext(ctx, "Failed to export parsed line", slog.Any("error", err))
 				return err
 			}
 		}
 		return nil
-	})
-	if err != nil {
-		slog.ErrorContext(ctx, "Log streaming failed", slog.Any("error", err))
+	}
+
+	// Every target is followed concurrently until the shutdown, the stages are
+	// safe for concurrent use
+	observers := catcher.Observers{monitor, selfMetrics}
+	var wg sync.WaitGroup
+	for _, target := range cfg.Targets {
+		wg.Go(func() {
+			catcher.Follow(ctx, target, observers, handleLine)
+		})
+	}
+	wg.Wait()
+
+	// The catchers are stopped, so nothing is exported anymore but what the
+	// stages and the exporters hold
+	shutdown(exporter, cfg.ShutdownGracePeriod.Std())
+}
+
+// shutdown flushes then closes the exporters within the grace period
+func shutdown(exporter *exporters.Exporters, grace time.Duration) {
+	slog.Info("Flushing exporters", slog.Duration("grace_period", grace))
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+
+	if err := exporter.Flush(ctx); err != nil {
+		slog.ErrorContext(ctx, "Failed to flush exporters", slog.Any("error", err))
+	}
+	if err := exporter.Close(ctx); err != nil {
+		slog.ErrorContext(ctx, "Failed to close exporters", slog.Any("error", err))
+	}
+	slog.Info("Stopped cantcost")
+}
+
+func setupLogger(level slog.Level) {
+	slog.SetDefault(
+		slog.New(
+			slogcontext.NewHandler(
+				slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+					Level: level,
+				}),
+			),
+		),
+	)
+}
+
+func newExporter(configs []config.Exporter) (*exporters.Exporters, error) {
+	var exporter = exporters.New()
+	for _, c := range configs {
+		var next exporters.Exporter
+		switch c.Type {
+		case "http":
+			httpExporter := exporters.NewHTTPExporter(c.URL, c.AuthHeader, c.BatchSize, c.FlushInterval.Std(), c.Fields)
+			secret, err := config.ReadSecret(c.SigningSecret, c.SigningSecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", c.Name, err)
+			}
+			if secret != nil {
+				httpExporter.Signer = signing.NewSigner(secret)
+			}
+			// The configuration is validated already
+			httpExporter.Encoding, _ = exporters.ParseEncoding(c.Encoding)
+			httpExporter.ContentEncoding, _ = exporters.ParseContentEncoding(c.ContentEncoding)
+			if httpExporter.Client, err = httpclient.New(c.TLS, c.OAuth2); err != nil {
+				return nil, fmt.Errorf("%s: %w", c.Name, err)
+			}
+			if httpExporter.Template, err = c.RequestTemplate(); err != nil {
+				return nil, fmt.Errorf("%s: %w", c.Name, err)
+			}
+			next = httpExporter
+			slog.Info("HTTP exporter configured",
+				slog.String("name", c.Name),
+				slog.String("url", c.URL),
+				slog.Int("batch_size", c.BatchSize),
+				slog.String("encoding", string(httpExporter.Encoding)),
+				slog.String("content_encoding", string(httpExporter.ContentEncoding)),
+				slog.Bool("signed", secret != nil),
+				slog.Bool("oauth2", c.OAuth2.Enabled()),
+				slog.Bool("mtls", c.TLS.CertFile != ""),
+				slog.Bool("templated", c.Templated()),
+				slog.Any("filter", c.Filter),
+				slog.Any("fields", c.Fields),
+			)
+		case "file":
+			next = exporters.NewFileExporter(c.Path, c.Fields)
+			slog.Info("File exporter configured",
+				slog.String("name", c.Name),
+				slog.String("path", c.Path),
+				slog.Any("filter", c.Filter),
+				slog.Any("fields", c.Fields),
+			)
+		}
+		if !c.Filter.Empty() {
+			next = exporters.NewFiltered(c.Filter.Filter(), next)
+		}
+		exporter.AddNamedExporter(c.Name, c.Timeout.Std(), next)
+	}
+	return exporter, nil
+}
+
+// newPricingRates returns nil if the pricing is not configured
+func newPricingRates(c config.Pricing) (pricing.Rates, error) {
+	if c.RatesFile != "" {
+		return pricing.LoadRates(c.RatesFile)
+	}
+	if c.USDPerMB > 0 {
+		return pricing.NewRates(pricing.Rate{
+			USDPerMB: c.USDPerMB,
+			USDPerCC: c.USDPerCC,
+		})
+	}
+	return nil, nil
+}
+
+func newDeadLetterSink(c config.DeadLetter) deadletter.Sink {
+	switch c.Type {
+	case "file":
+		slog.Info("File dead-letter sink configured",
+			slog.String("path", c.File),
+		)
+		return deadletter.NewFileSink(c.File)
+	case "http":
+		slog.Info("HTTP dead-letter sink configured",
+			slog.String("url", c.URL),
+		)
+		return deadletter.NewHTTPSink(c.URL, c.AuthHeader)
+	}
+	return nil
+}
+
+// quarantine writes the line which could not be processed to the dead-letter sink, if there is one
+func quarantine(ctx context.Context, sink deadletter.Sink, line string, err error) {
+	if sink == nil {
+		return
+	}
+	if err := sink.Write(ctx, deadletter.NewRecord(line, err)); err != nil {
+		slog.ErrorContext(ctx, "Failed to write dead-letter record", slog.Any("error", err))
 	}
 }
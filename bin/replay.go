@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+
+	"github.com/DLC-link/cantcost/internal/config"
+	"github.com/DLC-link/cantcost/internal/deadletter"
+	"github.com/DLC-link/cantcost/internal/parser"
+	"github.com/DLC-link/cantcost/internal/version"
+)
+
+// replay re-runs the quarantined lines of a dead-letter file through the current parser.
+//
+//	cantcost replay -file /tmp/cantcost-dead-letter.jsonl [-export]
+func replay(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	path := flags.String("file", cfg.DeadLetter.File, "dead-letter file to replay")
+	export := flags.Bool("export", false, "export the lines which are parsed successfully with the configured exporter")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		slog.Error("Failed to open dead-letter file", slog.String("path", *path), slog.Any("error", err))
+		return 1
+	}
+	defer file.Close()
+
+	ctx := context.Background()
+	exporter, err := newExporter(cfg.Exporters)
+	if err != nil {
+		slog.Error("Invalid exporter configuration", slog.Any("error", err))
+		return 1
+	}
+	if *export {
+		if err := exporter.Start(ctx); err != nil {
+			slog.Error("Failed to start exporters", slog.Any("error", err))
+			return 1
+		}
+	}
+
+	var parsed, failed int
+	err = deadletter.Read(file, func(record *deadletter.Record) error {
+		parsedLine, err := parser.ProcessLine(record.Line)
+		if err != nil {
+			failed++
+			slog.Warn("Line still fails to parse",
+				slog.Time("quarantined_at", record.Timestamp),
+				slog.String("quarantined_by", record.Version),
+				slog.String("original_error", record.Error),
+				slog.Any("error", err),
+			)
+			return nil
+		}
+
+		parsed++
+		slog.Info("Line parsed",
+			slog.Time("quarantined_at", record.Timestamp),
+			slog.String("quarantined_by", record.Version),
+			slog.Any("line", parsedLine.ToMessageLine()),
+		)
+		if *export {
+			if err := exporter.Export(ctx, &parsedLine); err != nil {
+				slog.Error("Failed to export parsed line", slog.Any("error", err))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Failed to read dead-letter file", slog.String("path", *path), slog.Any("error", err))
+		return 1
+	}
+
+	if *export {
+		shutdown(exporter, cfg.ShutdownGracePeriod.Std())
+	}
+
+	slog.Info("Replay finished",
+		slog.String("version", version.Version),
+		slog.Int("parsed", parsed),
+		slog.Int("failed", failed),
+	)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+package aggregator
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Trace)(nil)
+
+// Trace groups the EventCost lines by trace id and exports a TraceSummary line
+// per submission once the window since the first line of the trace elapsed.
+type Trace struct {
+	Window time.Duration `json:"window"`
+	exporters.Windowed
+
+	next   exporters.Exporter
+	traces map[string]*traceState
+	mutex  *sync.Mutex
+	now    func() time.Time
+}
+
+type traceState struct {
+	summary    parser.TraceSummary
+	recipients map[string]struct{}
+	openedAt   time.Time
+}
+
+func NewTrace(window time.Duration, next exporters.Exporter) *Trace {
+	t := &Trace{
+		Window: window,
+		next:   next,
+		traces: make(map[string]*traceState),
+		mutex:  &sync.Mutex{},
+		now:    time.Now,
+	}
+	t.Windowed = exporters.NewWindowed(tickInterval(window), t.flush)
+	return t
+}
+
+// Export adds the EventCost line to the summary of its trace, other lines are ignored
+func (t *Trace) Export(ctx context.Context, line *parser.Line) error {
+	if line.Kind != parser.KindEventCost || line.TraceID == "" {
+		return nil
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	state, ok := t.traces[line.TraceID]
+	if !ok {
+		state = &traceState{
+			summary: parser.TraceSummary{
+				TraceID:        line.TraceID,
+				FirstTimestamp: line.Timestamp,
+				LastTimestamp:  line.Timestamp,
+			},
+			recipients: make(map[string]struct{}),
+			openedAt:   t.now(),
+		}
+		t.traces[line.TraceID] = state
+	}
+
+	summary := &state.summary
+	summary.EventCount++
+	summary.TotalCost += line.CostDetails.EventCost
+	summary.EnvelopeCount += len(line.CostDetails.EnvelopesCost)
+	for _, envelope := range line.CostDetails.EnvelopesCost {
+		for _, recipient := range envelope.Recipients {
+			state.recipients[recipient.ID()] = struct{}{}
+		}
+	}
+	summary.DistinctRecipients = len(state.recipients)
+	if line.Timestamp.Before(summary.FirstTimestamp) {
+		summary.FirstTimestamp = line.Timestamp
+	}
+	if line.Timestamp.After(summary.LastTimestamp) {
+		summary.LastTimestamp = line.Timestamp
+	}
+
+	return nil
+}
+
+func (t *Trace) flush(ctx context.Context, all bool) {
+	var closed []parser.TraceSummary
+
+	t.mutex.Lock()
+	now := t.now()
+	for traceID, state := range t.traces {
+		if all || now.Sub(state.openedAt) >= t.Window {
+			closed = append(closed, state.summary)
+			delete(t.traces, traceID)
+		}
+	}
+	t.mutex.Unlock()
+
+	for i := range closed {
+		line := &parser.Line{
+			Timestamp:    closed[i].LastTimestamp,
+			TraceID:      closed[i].TraceID,
+			Kind:         parser.KindTraceSummary,
+			TraceSummary: &closed[i],
+		}
+		if err := t.next.Export(ctx, line); err != nil {
+			slog.ErrorContext(ctx, "Failed to export trace summary",
+				slog.String("trace_id", closed[i].TraceID), slog.Any("error", err))
+		}
+	}
+}
+
+// tickInterval checks the windows often enough to close them at most 10% late
+func tickInterval(window time.Duration) time.Duration {
+	return max(window/10, 100*time.Millisecond)
+}
@@ -0,0 +1,65 @@
+package aggregator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters/exporterstest"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func costLine(traceID string, timestamp time.Time, envelopes ...parser.EnvelopeCostDetails) *parser.Line {
+	details := &parser.EventCostDetails{EnvelopesCost: envelopes}
+	for _, envelope := range envelopes {
+		details.EventCost += envelope.FinalCost
+	}
+	return &parser.Line{
+		Timestamp:   timestamp,
+		TraceID:     traceID,
+		Kind:        parser.KindEventCost,
+		CostDetails: details,
+	}
+}
+
+func TestTrace(t *testing.T) {
+	capture := &exporterstest.Capture{}
+	aggregator := NewTrace(time.Minute, capture)
+	now := time.Date(2025, 12, 3, 17, 5, 0, 0, time.UTC)
+	aggregator.now = func() time.Time { return now }
+
+	mediator := parser.Recipient{Type: "MediatorGroupRecipient"}
+	member := parser.Recipient{Type: "MemberRecipient", Member: "PAR::iBTC-validator-1::1220fa8543db..."}
+
+	ctx := context.Background()
+	_ = aggregator.Export(ctx, costLine("trace-1", now.Add(2*time.Second),
+		parser.EnvelopeCostDetails{FinalCost: 1022, Recipients: []parser.Recipient{mediator}},
+		parser.EnvelopeCostDetails{FinalCost: 147, Recipients: []parser.Recipient{mediator, member}},
+	))
+	_ = aggregator.Export(ctx, costLine("trace-1", now,
+		parser.EnvelopeCostDetails{FinalCost: 343, Recipients: []parser.Recipient{mediator}},
+	))
+	_ = aggregator.Export(ctx, &parser.Line{TraceID: "trace-1", Kind: parser.KindTrafficState, TrafficState: &parser.TrafficState{}})
+
+	aggregator.flush(ctx, false)
+	if len(capture.Lines) != 0 {
+		t.Fatalf("Window should still be open, got %d summaries", len(capture.Lines))
+	}
+
+	now = now.Add(time.Minute)
+	aggregator.flush(ctx, false)
+	if len(capture.Lines) != 1 {
+		t.Fatalf("Summary count mismatch: got %d, want 1", len(capture.Lines))
+	}
+
+	summary := capture.Lines[0].TraceSummary
+	if capture.Lines[0].Kind != parser.KindTraceSummary || summary == nil {
+		t.Fatalf("Trace summary line expected, got %+v", capture.Lines[0])
+	}
+	if summary.EventCount != 2 || summary.TotalCost != 1512 || summary.EnvelopeCount != 3 || summary.DistinctRecipients != 2 {
+		t.Errorf("Summary mismatch: got %+v", summary)
+	}
+	if summary.LastTimestamp.Sub(summary.FirstTimestamp) != 2*time.Second {
+		t.Errorf("Timestamps mismatch: got %s - %s", summary.FirstTimestamp, summary.LastTimestamp)
+	}
+}
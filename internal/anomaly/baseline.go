@@ -0,0 +1,67 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+)
+
+// Method defines how the baseline of a metric is estimated
+type Method string
+
+const (
+	// MethodZScore keeps the mean and the variance of all the samples (Welford's algorithm)
+	MethodZScore Method = "zscore"
+	// MethodEWMA keeps an exponentially weighted moving mean and variance, so the baseline follows slow drifts
+	MethodEWMA Method = "ewma"
+)
+
+func ParseMethod(s string) (Method, error) {
+	switch Method(s) {
+	case MethodZScore, MethodEWMA:
+		return Method(s), nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownMethod, s)
+}
+
+type baseline struct {
+	method Method
+	alpha  float64
+
+	n        int
+	mean     float64
+	variance float64
+	// m2 is the sum of squared differences from the mean of the z-score method
+	m2 float64
+}
+
+func (b *baseline) stdDev() float64 {
+	// Guard against a zero deviation when all the samples were equal so far
+	return max(math.Sqrt(b.variance), b.mean*0.01, 1)
+}
+
+// score returns how many standard deviations x is above the mean
+func (b *baseline) score(x float64) float64 {
+	return (x - b.mean) / b.stdDev()
+}
+
+func (b *baseline) update(x float64) {
+	b.n++
+	switch b.method {
+	case MethodEWMA:
+		if b.n == 1 {
+			b.mean = x
+			return
+		}
+		diff := x - b.mean
+		increment := b.alpha * diff
+		b.mean += increment
+		b.variance = (1 - b.alpha) * (b.variance + diff*increment)
+	default:
+		diff := x - b.mean
+		b.mean += diff / float64(b.n)
+		b.m2 += diff * (x - b.mean)
+		if b.n > 1 {
+			b.variance = b.m2 / float64(b.n-1)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package anomaly
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Detector)(nil)
+
+// Key defines what the baselines are kept for
+type Key string
+
+const (
+	// KeySpan keeps a baseline per span name
+	KeySpan Key = "span"
+	// KeyShape keeps a baseline per span name and set of recipients, which
+	// approximates the shape of the command
+	KeyShape Key = "shape"
+)
+
+func ParseKey(s string) (Key, error) {
+	switch Key(s) {
+	case KeySpan, KeyShape:
+		return Key(s), nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownKey, s)
+}
+
+const (
+	MetricEventCost      = "event_cost"
+	MetricEnvelopeCount  = "envelope_count"
+	MetricRecipientCount = "recipient_count"
+)
+
+// Detector keeps a baseline of the event cost, envelope count and recipient
+// count of the submissions and exports an Anomaly line when a submission is
+// above the baseline by more than Threshold standard deviations. The baselines
+// of at most MaxKeys keys are kept, the least recently used ones are evicted,
+// since every new set of recipients is a new key with KeyShape.
+type Detector struct {
+	exporters.NopLifecycle
+
+	Method    Method  `json:"method"`
+	Key       Key     `json:"key"`
+	Threshold float64 `json:"threshold"`
+	// Warmup is the number of samples a baseline needs before it flags outliers
+	Warmup int `json:"warmup"`
+	// Alpha is the smoothing factor of the EWMA method
+	Alpha   float64 `json:"alpha"`
+	MaxKeys int     `json:"max_keys"`
+
+	next exporters.Exporter
+	// baselines indexes the elements of recent, whose front is the most
+	// recently used key
+	baselines map[string]*list.Element
+	recent    *list.List
+	mutex     *sync.Mutex
+}
+
+// keyBaselines are the baselines of the metrics of a key
+type keyBaselines struct {
+	key     string
+	metrics map[string]*baseline
+}
+
+func New(method Method, key Key, threshold float64, warmup int, alpha float64, next exporters.Exporter) *Detector {
+	return &Detector{
+		Method:    method,
+		Key:       key,
+		Threshold: threshold,
+		Warmup:    warmup,
+		Alpha:     alpha,
+		MaxKeys:   10000,
+		next:      next,
+		baselines: make(map[string]*list.Element),
+		recent:    list.New(),
+		mutex:     &sync.Mutex{},
+	}
+}
+
+// Export checks the EventCost line against the baselines, other lines are ignored
+func (d *Detector) Export(ctx context.Context, line *parser.Line) error {
+	if line.Kind != parser.KindEventCost {
+		return nil
+	}
+
+	key, recipients := d.key(line)
+	metrics := []struct {
+		name  string
+		value float64
+	}{
+		{MetricEventCost, float64(line.CostDetails.EventCost)},
+		{MetricEnvelopeCount, float64(len(line.CostDetails.EnvelopesCost))},
+		{MetricRecipientCount, float64(recipients)},
+	}
+
+	var anomalies []parser.Anomaly
+	d.mutex.Lock()
+	baselines := d.lookup(key)
+	for _, metric := range metrics {
+		b, ok := baselines.metrics[metric.name]
+		if !ok {
+			b = &baseline{method: d.Method, alpha: d.Alpha}
+			baselines.metrics[metric.name] = b
+		}
+		if b.n >= d.Warmup {
+			if score := b.score(metric.value); score > d.Threshold {
+				anomalies = append(anomalies, parser.Anomaly{
+					Key:      key,
+					Metric:   metric.name,
+					Method:   string(d.Method),
+					Value:    metric.value,
+					Mean:     b.mean,
+					StdDev:   b.stdDev(),
+					Score:    score,
+					Baseline: b.n,
+				})
+			}
+		}
+		b.update(metric.value)
+	}
+	d.mutex.Unlock()
+
+	for i := range anomalies {
+		slog.WarnContext(ctx, "Anomalous submission cost",
+			slog.String("trace_id", line.TraceID),
+			slog.String("key", anomalies[i].Key),
+			slog.String("metric", anomalies[i].Metric),
+			slog.Float64("value", anomalies[i].Value),
+			slog.Float64("score", anomalies[i].Score),
+		)
+		alert := &parser.Line{
+			DockerTimestamp: line.DockerTimestamp,
+			Timestamp:       line.Timestamp,
+			SpanID:          line.SpanID,
+			SpanParentID:    line.SpanParentID,
+			TraceID:         line.TraceID,
+			SpanName:        line.SpanName,
+			Participant:     line.Participant,
+			Synchronizer:    line.Synchronizer,
+			Kind:            parser.KindAnomaly,
+			Anomaly:         &anomalies[i],
+		}
+		if err := d.next.Export(ctx, alert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the baselines of the key and marks it as the most recently
+// used, the mutex must be held
+func (d *Detector) lookup(key string) *keyBaselines {
+	if element, ok := d.baselines[key]; ok {
+		d.recent.MoveToFront(element)
+		return element.Value.(*keyBaselines)
+	}
+
+	baselines := &keyBaselines{key: key, metrics: make(map[string]*baseline)}
+	d.baselines[key] = d.recent.PushFront(baselines)
+	for d.recent.Len() > d.MaxKeys {
+		oldest := d.recent.Back()
+		d.recent.Remove(oldest)
+		delete(d.baselines, oldest.Value.(*keyBaselines).key)
+	}
+	return baselines
+}
+
+// key returns the baseline key of the line and its number of distinct recipients
+func (d *Detector) key(line *parser.Line) (string, int) {
+	seen := make(map[string]struct{})
+	for _, envelope := range line.CostDetails.EnvelopesCost {
+		for _, recipient := range envelope.Recipients {
+			seen[recipient.ID()] = struct{}{}
+		}
+	}
+
+	if d.Key != KeyShape {
+		return line.SpanName, len(seen)
+	}
+
+	recipients := make([]string, 0, len(seen))
+	for id := range seen {
+		recipients = append(recipients, id)
+	}
+	sort.Strings(recipients)
+	h := fnv.New64a()
+	for _, id := range recipients {
+		h.Write([]byte(id))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%s/%016x", line.SpanName, h.Sum64()), len(seen)
+}
@@ -0,0 +1,75 @@
+package anomaly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DLC-link/cantcost/internal/exporters/exporterstest"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func costLine(cost int, envelopes int) *parser.Line {
+	details := &parser.EventCostDetails{EventCost: cost}
+	for range envelopes {
+		details.EnvelopesCost = append(details.EnvelopesCost, parser.EnvelopeCostDetails{
+			FinalCost:  cost / envelopes,
+			Recipients: []parser.Recipient{{Type: "MediatorGroupRecipient"}},
+		})
+	}
+	return &parser.Line{
+		SpanName:    "SequencerClient.sendAsync",
+		Kind:        parser.KindEventCost,
+		CostDetails: details,
+	}
+}
+
+func TestDetector(t *testing.T) {
+	for _, method := range []Method{MethodZScore, MethodEWMA} {
+		capture := &exporterstest.Capture{}
+		detector := New(method, KeySpan, 3, 20, 0.1, capture)
+
+		ctx := context.Background()
+		for i := range 50 {
+			_ = detector.Export(ctx, costLine(7000+(i%5)*100, 4))
+		}
+		if len(capture.Lines) != 0 {
+			t.Fatalf("%s: Anomaly count mismatch in the baseline: got %d, want 0", method, len(capture.Lines))
+		}
+
+		_ = detector.Export(ctx, costLine(70000, 40))
+		if len(capture.Lines) != 2 {
+			t.Fatalf("%s: Anomaly count mismatch: got %d, want 2", method, len(capture.Lines))
+		}
+		metrics := map[string]bool{}
+		for _, line := range capture.Lines {
+			if line.Kind != parser.KindAnomaly {
+				t.Errorf("%s: Kind mismatch: got %s, want %s", method, line.Kind, parser.KindAnomaly)
+			}
+			metrics[line.Anomaly.Metric] = true
+		}
+		if !metrics[MetricEventCost] || !metrics[MetricEnvelopeCount] {
+			t.Errorf("%s: Metrics mismatch: got %v, want %s and %s", method, metrics, MetricEventCost, MetricEnvelopeCount)
+		}
+	}
+}
+
+func TestDetectorEvictsLeastRecentlyUsedKeys(t *testing.T) {
+	detector := New(MethodZScore, KeySpan, 3, 0, 0.1, &exporterstest.Capture{})
+	detector.MaxKeys = 2
+	ctx := context.Background()
+	for _, span := range []string{"a", "b", "a", "c"} {
+		line := costLine(100, 1)
+		line.SpanName = span
+		_ = detector.Export(ctx, line)
+	}
+
+	if len(detector.baselines) != 2 || detector.recent.Len() != 2 {
+		t.Fatalf("Key count mismatch: got %d, want 2", len(detector.baselines))
+	}
+	// b is the least recently used key when c comes in
+	for span, kept := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := detector.baselines[span]; ok != kept {
+			t.Errorf("Baseline of %s kept mismatch: got %v, want %v", span, ok, kept)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package anomaly
+
+import "errors"
+
+var (
+	ErrUnknownMethod = errors.New("unknown anomaly detection method")
+	ErrUnknownKey    = errors.New("unknown anomaly detection key")
+)
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+type AttributionQuerier interface {
+	Query(from time.Time, to time.Time) []parser.CounterpartyCost
+}
+
+type AttributionResponse struct {
+	From           time.Time                 `json:"from"`
+	To             time.Time                 `json:"to"`
+	Counterparties []parser.CounterpartyCost `json:"counterparties"`
+}
+
+// AttributionHandler serves the per-counterparty totals:
+//
+//	GET /v1/attribution?from=2025-12-03T00:00:00Z&to=2025-12-04T00:00:00Z
+//
+// The range defaults to the last 24 hours.
+func AttributionHandler(querier AttributionQuerier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		to, err := parseTimeParam(r, "to", time.Now())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		from, err := parseTimeParam(r, "from", to.Add(-24*time.Hour))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, AttributionResponse{
+			From:           from,
+			To:             to,
+			Counterparties: querier.Query(from, to),
+		})
+	})
+}
+
+func parseTimeParam(r *http.Request, name string, fallback time.Time) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return fallback, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s parameter: %w", name, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/DLC-link/cantcost/internal/budget"
+)
+
+type BudgetQuerier interface {
+	Usages() []budget.Usage
+}
+
+type BudgetResponse struct {
+	Participants []budget.Usage `json:"participants"`
+}
+
+// BudgetHandler serves the current budget usage of the participants:
+//
+//	GET /v1/budget
+func BudgetHandler(querier BudgetQuerier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, BudgetResponse{
+			Participants: querier.Usages(),
+		})
+	})
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/parser"
+	"github.com/DLC-link/cantcost/internal/store"
+)
+
+// defaultLimit is the number of events listed when the request does not say otherwise
+const defaultLimit = 100
+
+type EventQuerier interface {
+	Find(f filter.Filter, limit int) []*parser.MessageLine
+	Totals(f filter.Filter) store.Totals
+}
+
+type TraceResponse struct {
+	TraceID   string                `json:"trace_id"`
+	TotalCost int                   `json:"total_cost"`
+	Events    []*parser.MessageLine `json:"events"`
+}
+
+type EventsResponse struct {
+	Count  int                   `json:"count"`
+	Events []*parser.MessageLine `json:"events"`
+}
+
+// TraceHandler serves the recent events of a trace and their total cost:
+//
+//	GET /v1/traces/{traceID}
+func TraceHandler(querier EventQuerier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID := r.PathValue("traceID")
+		events := querier.Find(filter.Filter{TraceID: traceID}, 0)
+		if len(events) == 0 {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no recent events for trace %s", traceID))
+			return
+		}
+
+		response := TraceResponse{
+			TraceID: traceID,
+			Events:  events,
+		}
+		for _, event := range events {
+			if event.CostDetails != nil {
+				response.TotalCost += event.CostDetails.EventCost
+			}
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+}
+
+// EventsHandler lists the recent events, the newest first:
+//
+//	GET /v1/events?from=&to=&kind=&synchronizer=&min_cost=&recipient=&span_name=&level=&limit=
+func EventsHandler(querier EventQuerier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, err := filter.FromQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		limit, err := parseLimit(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		events := querier.Find(f, limit)
+		writeJSON(w, http.StatusOK, EventsResponse{
+			Count:  len(events),
+			Events: events,
+		})
+	})
+}
+
+// TotalsHandler aggregates the costs of the recent events:
+//
+//	GET /v1/totals?from=&to=&kind=&synchronizer=&min_cost=&recipient=
+func TotalsHandler(querier EventQuerier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, err := filter.FromQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, querier.Totals(f))
+	})
+}
+
+func parseLimit(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit <= 0 {
+		return 0, errors.New("invalid limit parameter: must be a positive integer")
+	}
+	return limit, nil
+}
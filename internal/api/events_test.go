@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+	"github.com/DLC-link/cantcost/internal/store"
+)
+
+func newStore(t *testing.T) *store.Store {
+	t.Helper()
+	s := store.New(10, time.Hour)
+	timestamp := time.Date(2025, 12, 3, 17, 5, 0, 0, time.UTC)
+	for i, cost := range []int{100, 200, 300} {
+		line := &parser.Line{
+			Timestamp:   timestamp.Add(time.Duration(i) * time.Second),
+			TraceID:     "trace-a",
+			Kind:        parser.KindEventCost,
+			CostDetails: &parser.EventCostDetails{EventCost: cost},
+		}
+		if i == 2 {
+			line.TraceID = "trace-b"
+		}
+		if err := s.Export(t.Context(), line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func serve(handler http.Handler, pattern string, target string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.Handle(pattern, handler)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestTraceHandler(t *testing.T) {
+	querier := newStore(t)
+
+	recorder := serve(TraceHandler(querier), "GET /v1/traces/{traceID}", "/v1/traces/trace-a")
+	var response TraceResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil || recorder.Code != http.StatusOK {
+		t.Fatalf("GET trace-a = %d, %v", recorder.Code, err)
+	}
+	if response.TraceID != "trace-a" || len(response.Events) != 2 || response.TotalCost != 300 {
+		t.Errorf("GET trace-a = %+v, want 2 events costing 300", response)
+	}
+
+	recorder = serve(TraceHandler(querier), "GET /v1/traces/{traceID}", "/v1/traces/unknown")
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("GET unknown trace = %d, want 404", recorder.Code)
+	}
+}
+
+func TestEventsHandler(t *testing.T) {
+	querier := newStore(t)
+
+	recorder := serve(EventsHandler(querier), "GET /v1/events", "/v1/events?min_cost=150&limit=1")
+	var response EventsResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil || recorder.Code != http.StatusOK {
+		t.Fatalf("GET events = %d, %v", recorder.Code, err)
+	}
+	// The newest first
+	if response.Count != 1 || response.Events[0].CostDetails.EventCost != 300 {
+		t.Errorf("GET events = %+v, want the event costing 300", response)
+	}
+
+	for _, query := range []string{"min_cost=cheap", "limit=0", "limit=ten", "from=yesterday"} {
+		recorder := serve(EventsHandler(querier), "GET /v1/events", "/v1/events?"+query)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("GET events?%s = %d, want 400", query, recorder.Code)
+		}
+	}
+}
+
+func TestTotalsHandler(t *testing.T) {
+	querier := newStore(t)
+
+	recorder := serve(TotalsHandler(querier), "GET /v1/totals", "/v1/totals?from=2025-12-03T17:05:01Z")
+	var totals store.Totals
+	if err := json.NewDecoder(recorder.Body).Decode(&totals); err != nil || recorder.Code != http.StatusOK {
+		t.Fatalf("GET totals = %d, %v", recorder.Code, err)
+	}
+	if totals.EventCount != 2 || totals.TotalCost != 500 {
+		t.Errorf("GET totals = %+v, want 2 events costing 500", totals)
+	}
+
+	recorder = serve(TotalsHandler(querier), "GET /v1/totals", "/v1/totals?to=tomorrow")
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("GET totals with an invalid to = %d, want 400", recorder.Code)
+	}
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+)
+
+type ExportersQuerier interface {
+	Stats() []exporters.Stats
+}
+
+type ExportersResponse struct {
+	Exporters []exporters.Stats `json:"exporters"`
+}
+
+// ExportersHandler serves the success and failure counts of the exporters:
+//
+//	GET /v1/exporters
+func ExportersHandler(querier ExportersQuerier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, ExportersResponse{
+			Exporters: querier.Stats(),
+		})
+	})
+}
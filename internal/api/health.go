@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// readinessTimeout bounds the reachability check of the exporters, below the
+// default timeout of the Kubernetes probes
+const readinessTimeout = 800 * time.Millisecond
+
+type HealthChecker interface {
+	Live() error
+	Ready() error
+}
+
+type ReachabilityChecker interface {
+	Check(ctx context.Context) error
+}
+
+type HealthResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AliveHandler answers as long as the process serves requests:
+//
+//	GET /healthz
+func AliveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, nil)
+	})
+}
+
+// LivenessHandler fails when the log streams are stuck, so that Kubernetes
+// restarts cantcost:
+//
+//	GET /livez
+func LivenessHandler(checker HealthChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, checker.Live())
+	})
+}
+
+// ReadinessHandler fails until the log streams are attached, or when an
+// exporter is unreachable:
+//
+//	GET /readyz
+func ReadinessHandler(checker HealthChecker, exporters ReachabilityChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := checker.Ready(); err != nil {
+			writeHealth(w, err)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		defer cancel()
+		writeHealth(w, exporters.Check(ctx))
+	})
+}
+
+func writeHealth(w http.ResponseWriter, err error) {
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, HealthResponse{Status: "failing", Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, HealthResponse{Status: "ok"})
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeHealth struct {
+	live, ready error
+}
+
+func (f fakeHealth) Live() error  { return f.live }
+func (f fakeHealth) Ready() error { return f.ready }
+
+type fakeReachability struct {
+	err error
+}
+
+func (f fakeReachability) Check(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("the check has no deadline")
+	}
+	return f.err
+}
+
+func TestHealthHandlers(t *testing.T) {
+	errStale := errors.New("stale")
+	tests := []struct {
+		name    string
+		handler http.Handler
+		status  int
+	}{
+		{"alive", AliveHandler(), http.StatusOK},
+		{"live", LivenessHandler(fakeHealth{}), http.StatusOK},
+		{"stale", LivenessHandler(fakeHealth{live: errStale}), http.StatusServiceUnavailable},
+		{"ready", ReadinessHandler(fakeHealth{}, fakeReachability{}), http.StatusOK},
+		{"not attached", ReadinessHandler(fakeHealth{ready: errStale}, fakeReachability{}), http.StatusServiceUnavailable},
+		{"exporter unreachable", ReadinessHandler(fakeHealth{}, fakeReachability{err: errStale}), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			tt.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			if recorder.Code != tt.status {
+				t.Errorf("GET = %d, want %d: %s", recorder.Code, tt.status, recorder.Body)
+			}
+		})
+	}
+}
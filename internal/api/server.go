@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Server is the HTTP server of cantcost, the features register their endpoints on it
+type Server struct {
+	Addr string `json:"addr"`
+
+	mux *http.ServeMux
+}
+
+func New(addr string) *Server {
+	return &Server{
+		Addr: addr,
+		mux:  http.NewServeMux(),
+	}
+}
+
+// Handle registers the handler for the pattern, see http.ServeMux for the syntax
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Run serves the requests until the context is done
+func (s *Server) Run(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.ErrorContext(ctx, "Failed to shut down HTTP server", slog.Any("error", err))
+		}
+	}()
+
+	slog.InfoContext(ctx, "HTTP server listening", slog.String("addr", s.Addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Failed to write HTTP response", slog.Any("error", err))
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
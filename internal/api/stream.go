@@ -0,0 +1,127 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/stream"
+	"golang.org/x/net/websocket"
+)
+
+// streamKeepAlive is the interval of the keep-alive comments of an idle stream
+const streamKeepAlive = 15 * time.Second
+
+type StreamSubscriber interface {
+	Subscribe(f filter.Filter) *stream.Subscriber
+	Unsubscribe(subscriber *stream.Subscriber)
+}
+
+// StreamHandler pushes the exported lines matching the query filters as server-sent events:
+//
+//	GET /v1/stream?kind=&synchronizer=&min_cost=&recipient=&trace_id=
+func StreamHandler(broker StreamSubscriber) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, err := filter.FromQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		subscriber := broker.Subscribe(f)
+		defer broker.Unsubscribe(subscriber)
+
+		events, err := stream.NewEventWriter(w)
+		if err != nil {
+			return
+		}
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+
+		for {
+			var data []byte
+			var ok bool
+			select {
+			case <-r.Context().Done():
+				return
+			case data, ok = <-subscriber.Lines():
+			case <-keepAlive.C:
+				ok = true
+			}
+
+			var err error
+			switch {
+			case !ok:
+				// Disconnected as a slow consumer, or the broker is closing on shutdown
+				if subscriber.Evicted() {
+					_ = events.Event("error", []byte(`{"error":"slow consumer disconnected"}`))
+				}
+				return
+			case data == nil:
+				err = events.Comment("keep-alive")
+			default:
+				err = events.Event("line", data)
+			}
+			if err != nil {
+				slog.DebugContext(r.Context(), "Stream client disconnected", slog.Any("error", err))
+				return
+			}
+		}
+	})
+}
+
+// WebSocketHandler pushes the exported lines matching the query filters as
+// WebSocket text messages:
+//
+//	GET /v1/stream/ws?kind=&synchronizer=&min_cost=&recipient=&trace_id=
+func WebSocketHandler(broker StreamSubscriber) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, err := filter.FromQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		// The server does not check the Origin header, so that non-browser clients can connect
+		server := websocket.Server{
+			Handler: func(conn *websocket.Conn) {
+				subscriber := broker.Subscribe(f)
+				defer broker.Unsubscribe(subscriber)
+				serveWebSocket(conn, subscriber)
+			},
+		}
+		server.ServeHTTP(w, r)
+	})
+}
+
+func serveWebSocket(conn *websocket.Conn, subscriber *stream.Subscriber) {
+	// The client does not send anything, reading only detects when it goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard []byte
+		for {
+			if err := websocket.Message.Receive(conn, &discard); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case data, ok := <-subscriber.Lines():
+			if !ok {
+				slog.Debug("Closing WebSocket of disconnected subscriber", slog.Bool("slow_consumer", subscriber.Evicted()))
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(stream.WriteTimeout))
+			if err := websocket.Message.Send(conn, string(data)); err != nil {
+				slog.Debug("WebSocket client disconnected", slog.Any("error", err))
+				return
+			}
+		}
+	}
+}
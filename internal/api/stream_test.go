@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/parser"
+	"github.com/DLC-link/cantcost/internal/stream"
+	"golang.org/x/net/websocket"
+)
+
+// floodingBroker exports lines as soon as a client subscribes, before the
+// handler reads any of them, so that the client falls behind deterministically
+type floodingBroker struct {
+	*stream.Broker
+	lines []*parser.Line
+}
+
+func (b *floodingBroker) Subscribe(f filter.Filter) *stream.Subscriber {
+	subscriber := b.Broker.Subscribe(f)
+	for _, line := range b.lines {
+		_ = b.Export(context.Background(), line)
+	}
+	return subscriber
+}
+
+func TestStreamHandlerDisconnectsSlowConsumer(t *testing.T) {
+	line := func(cost int) *parser.Line {
+		return &parser.Line{TraceID: "a", Kind: parser.KindEventCost, CostDetails: &parser.EventCostDetails{EventCost: cost}}
+	}
+	broker := &floodingBroker{
+		Broker: stream.New(1),
+		// The second line overflows the buffer of one line
+		lines: []*parser.Line{line(100), line(200)},
+	}
+
+	recorder := httptest.NewRecorder()
+	StreamHandler(broker).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/stream?min_cost=1", nil))
+
+	if recorder.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", recorder.Header().Get("Content-Type"))
+	}
+	events := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n\n")
+	if len(events) != 2 ||
+		!strings.HasPrefix(events[0], "event: line\ndata: {") || !strings.Contains(events[0], `"event_cost":100`) ||
+		events[1] != "event: error\ndata: {\"error\":\"slow consumer disconnected\"}" {
+		t.Errorf("stream = %q, want the buffered line then the disconnection", events)
+	}
+	if broker.Subscribers() != 0 {
+		t.Errorf("%d subscribers left, want 0", broker.Subscribers())
+	}
+}
+
+// closingBroker closes as soon as a client subscribes, as on shutdown
+type closingBroker struct {
+	*stream.Broker
+}
+
+func (b *closingBroker) Subscribe(f filter.Filter) *stream.Subscriber {
+	subscriber := b.Broker.Subscribe(f)
+	_ = b.Close(context.Background())
+	return subscriber
+}
+
+func TestStreamHandlerEndsOnShutdown(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	StreamHandler(&closingBroker{Broker: stream.New(1)}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/stream", nil))
+
+	if recorder.Code != http.StatusOK || strings.Contains(recorder.Body.String(), "error") {
+		t.Errorf("GET = %d with %q, want 200 without an error event", recorder.Code, recorder.Body)
+	}
+}
+
+func TestStreamHandlerRejectsInvalidFilter(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	StreamHandler(stream.New(1)).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/stream?min_cost=cheap", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("GET = %d, want 400", recorder.Code)
+	}
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	broker := stream.New(10)
+	server := httptest.NewServer(WebSocketHandler(broker))
+	defer server.Close()
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/?trace_id=a", "", server.URL)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+	for broker.Subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx := context.Background()
+	_ = broker.Export(ctx, &parser.Line{TraceID: "b", Kind: parser.KindEventCost, CostDetails: &parser.EventCostDetails{EventCost: 100}})
+	_ = broker.Export(ctx, &parser.Line{TraceID: "a", Kind: parser.KindEventCost, CostDetails: &parser.EventCostDetails{EventCost: 200}})
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var message string
+	if err := websocket.Message.Receive(conn, &message); err != nil {
+		t.Fatalf("Receive() error: %v", err)
+	}
+	if !strings.Contains(message, `"trace_id":"a"`) {
+		t.Errorf("message = %s, want the line of trace a only", message)
+	}
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/DLC-link/cantcost/internal/correlation"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+// maxSubmissionsBody bounds the size of a submissions request
+const maxSubmissionsBody = 1 << 20
+
+type SubmissionRegistrar interface {
+	Register(ctx context.Context, submission parser.Submission) error
+}
+
+// SubmissionRequest is a Ledger API command submission reported by an
+// application. The trace id is taken from the traceparent, or from trace_id if
+// there is none.
+type SubmissionRequest struct {
+	CommandID     string `json:"command_id"`
+	SubmissionID  string `json:"submission_id"`
+	Traceparent   string `json:"traceparent"`
+	TraceID       string `json:"trace_id"`
+	User          string `json:"user"`
+	ApplicationID string `json:"application_id"`
+	TemplateID    string `json:"template_id"`
+	Choice        string `json:"choice"`
+}
+
+type SubmissionsResponse struct {
+	Accepted int      `json:"accepted"`
+	TraceIDs []string `json:"trace_ids"`
+}
+
+// SubmissionsHandler records the submissions reported by the applications, a
+// single one or a JSON array of them:
+//
+//	POST /v1/submissions
+//
+// If authHeader is not empty, the requests must carry it as their Authorization header.
+func SubmissionsHandler(registrar SubmissionRegistrar, authHeader string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authHeader != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(authHeader)) != 1 {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("invalid authorization header"))
+			return
+		}
+
+		requests, err := decodeSubmissions(http.MaxBytesReader(w, r.Body, maxSubmissionsBody))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Validate the whole batch before registering any of it
+		submissions := make([]parser.Submission, 0, len(requests))
+		for i, request := range requests {
+			submission, err := request.toSubmission()
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("submission %d: %w", i, err))
+				return
+			}
+			submissions = append(submissions, submission)
+		}
+
+		response := SubmissionsResponse{TraceIDs: make([]string, 0, len(submissions))}
+		for _, submission := range submissions {
+			if err := registrar.Register(r.Context(), submission); err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			response.Accepted++
+			response.TraceIDs = append(response.TraceIDs, submission.TraceID)
+		}
+		writeJSON(w, http.StatusAccepted, response)
+	})
+}
+
+func decodeSubmissions(body io.Reader) ([]SubmissionRequest, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []SubmissionRequest
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &requests)
+	} else {
+		var request SubmissionRequest
+		err = json.Unmarshal(trimmed, &request)
+		requests = append(requests, request)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid submissions body: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *SubmissionRequest) toSubmission() (parser.Submission, error) {
+	traceID := r.TraceID
+	if r.Traceparent != "" {
+		var err error
+		if traceID, err = correlation.ParseTraceparent(r.Traceparent); err != nil {
+			return parser.Submission{}, err
+		}
+	}
+	if traceID == "" {
+		return parser.Submission{}, correlation.ErrMissingTraceID
+	}
+	if r.CommandID == "" {
+		return parser.Submission{}, fmt.Errorf("missing command_id")
+	}
+
+	return parser.Submission{
+		TraceID:       traceID,
+		CommandID:     r.CommandID,
+		SubmissionID:  r.SubmissionID,
+		User:          r.User,
+		ApplicationID: r.ApplicationID,
+		TemplateID:    r.TemplateID,
+		Choice:        r.Choice,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+type captureRegistrar struct {
+	submissions []parser.Submission
+}
+
+func (c *captureRegistrar) Register(ctx context.Context, submission parser.Submission) error {
+	c.submissions = append(c.submissions, submission)
+	return nil
+}
+
+func TestSubmissionsHandler(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tests := []struct {
+		name       string
+		auth       string
+		body       string
+		status     int
+		registered int
+	}{
+		{
+			name:       "single",
+			auth:       "Bearer secret",
+			body:       `{"command_id":"cmd-1","traceparent":"` + traceparent + `"}`,
+			status:     http.StatusAccepted,
+			registered: 1,
+		},
+		{
+			name:       "batch",
+			auth:       "Bearer secret",
+			body:       `[{"command_id":"cmd-1","trace_id":"a"},{"command_id":"cmd-2","trace_id":"b"}]`,
+			status:     http.StatusAccepted,
+			registered: 2,
+		},
+		{
+			name:   "invalid submission in the batch",
+			auth:   "Bearer secret",
+			body:   `[{"command_id":"cmd-1","trace_id":"a"},{"command_id":"cmd-2"}]`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid traceparent",
+			auth:   "Bearer secret",
+			body:   `{"command_id":"cmd-1","traceparent":"00-nothex"}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid JSON",
+			auth:   "Bearer secret",
+			body:   `{"command_id":`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "wrong authorization",
+			auth:   "Bearer other",
+			body:   `{"command_id":"cmd-1","trace_id":"a"}`,
+			status: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registrar := &captureRegistrar{}
+			req := httptest.NewRequest(http.MethodPost, "/v1/submissions", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", tt.auth)
+			recorder := httptest.NewRecorder()
+			SubmissionsHandler(registrar, "Bearer secret").ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.status {
+				t.Errorf("POST = %d, want %d: %s", recorder.Code, tt.status, recorder.Body)
+			}
+			// A batch is registered entirely or not at all
+			if len(registrar.submissions) != tt.registered {
+				t.Errorf("registered %d submissions, want %d", len(registrar.submissions), tt.registered)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package attribution
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Attribution)(nil)
+
+// Attribution splits the cost of every envelope across its recipients and keeps
+// per-counterparty totals in tumbling windows of the event timestamps. Closed
+// windows are exported as CounterpartyCost lines and kept for the retention
+// period, so they can be queried.
+//
+// A window is closed once the watermark passes its end, as in the rollups. The
+// watermark is the latest event timestamp (or the wall clock, if it is ahead)
+// minus the allowed lateness. Lines which arrive after their window was closed
+// are dropped and counted as late, so that every window is exported once.
+type Attribution struct {
+	Policy          Policy        `json:"policy"`
+	Window          time.Duration `json:"window"`
+	Retention       time.Duration `json:"retention"`
+	AllowedLateness time.Duration `json:"allowed_lateness"`
+	exporters.Windowed
+
+	next      exporters.Exporter
+	open      map[time.Time]map[string]*parser.CounterpartyCost
+	closed    []parser.CounterpartyCost
+	latest    time.Time
+	watermark time.Time
+	late      int
+	mutex     *sync.Mutex
+	now       func() time.Time
+}
+
+func New(policy Policy, window time.Duration, retention time.Duration, next exporters.Exporter) *Attribution {
+	a := &Attribution{
+		Policy:    policy,
+		Window:    window,
+		Retention: retention,
+		next:      next,
+		open:      make(map[time.Time]map[string]*parser.CounterpartyCost),
+		mutex:     &sync.Mutex{},
+		now:       time.Now,
+	}
+	a.Windowed = exporters.NewWindowed(max(window/10, time.Second), a.flush)
+	return a
+}
+
+// Export attributes the cost of the EventCost line, other lines are ignored
+func (a *Attribution) Export(ctx context.Context, line *parser.Line) error {
+	if line.Kind != parser.KindEventCost {
+		return nil
+	}
+
+	timestamp := line.Timestamp
+	if timestamp.IsZero() {
+		timestamp = a.now()
+	}
+	start := timestamp.Truncate(a.Window)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if timestamp.After(a.latest) {
+		a.latest = timestamp
+	}
+	if !start.Add(a.Window).After(a.watermark) {
+		a.late++
+		slog.DebugContext(ctx, "Dropped late event from attribution",
+			slog.String("trace_id", line.TraceID),
+			slog.Time("timestamp", timestamp),
+		)
+		return nil
+	}
+
+	window, ok := a.open[start]
+	if !ok {
+		window = make(map[string]*parser.CounterpartyCost)
+		a.open[start] = window
+	}
+
+	for _, envelope := range line.CostDetails.EnvelopesCost {
+		share := a.Policy.Share(envelope)
+		for _, recipient := range envelope.Recipients {
+			id := recipient.ID()
+			cost, ok := window[id]
+			if !ok {
+				cost = &parser.CounterpartyCost{
+					Counterparty: id,
+					Policy:       string(a.Policy),
+					WindowStart:  start,
+					WindowEnd:    start.Add(a.Window),
+				}
+				window[id] = cost
+			}
+			cost.AttributedCost += share
+			cost.EnvelopeCount++
+		}
+	}
+
+	return nil
+}
+
+// Late returns the number of lines which arrived after their window was closed
+func (a *Attribution) Late() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.late
+}
+
+func (a *Attribution) flush(ctx context.Context, all bool) {
+	var closed []parser.CounterpartyCost
+
+	a.mutex.Lock()
+	now := a.now()
+	reference := now
+	if a.latest.After(reference) {
+		reference = a.latest
+	}
+	if watermark := reference.Add(-a.AllowedLateness); watermark.After(a.watermark) {
+		a.watermark = watermark
+	}
+	for start, window := range a.open {
+		end := start.Add(a.Window)
+		if !all && end.After(a.watermark) {
+			continue
+		}
+		// A window flushed before the watermark passed its end is closed too
+		if end.After(a.watermark) {
+			a.watermark = end
+		}
+		for _, cost := range window {
+			closed = append(closed, *cost)
+		}
+		delete(a.open, start)
+	}
+	sortByCost(closed)
+	a.closed = append(a.closed, closed...)
+
+	// Drop the windows which are out of the retention period
+	kept := a.closed[:0]
+	for _, cost := range a.closed {
+		if now.Sub(cost.WindowEnd) < a.Retention {
+			kept = append(kept, cost)
+		}
+	}
+	a.closed = kept
+	a.mutex.Unlock()
+
+	for i := range closed {
+		line := &parser.Line{
+			Timestamp:        closed[i].WindowEnd,
+			Kind:             parser.KindCounterpartyCost,
+			CounterpartyCost: &closed[i],
+		}
+		if err := a.next.Export(ctx, line); err != nil {
+			slog.ErrorContext(ctx, "Failed to export counterparty cost",
+				slog.String("counterparty", closed[i].Counterparty), slog.Any("error", err))
+		}
+	}
+}
+
+// Query returns the per-counterparty totals of the windows which overlap with
+// [from, to), both the closed and the open ones, the most expensive first.
+func (a *Attribution) Query(from time.Time, to time.Time) []parser.CounterpartyCost {
+	totals := make(map[string]*parser.CounterpartyCost)
+	add := func(cost *parser.CounterpartyCost) {
+		if !cost.WindowEnd.After(from) || !cost.WindowStart.Before(to) {
+			return
+		}
+		total, ok := totals[cost.Counterparty]
+		if !ok {
+			total = &parser.CounterpartyCost{
+				Counterparty: cost.Counterparty,
+				Policy:       cost.Policy,
+				WindowStart:  cost.WindowStart,
+				WindowEnd:    cost.WindowEnd,
+			}
+			totals[cost.Counterparty] = total
+		}
+		total.AttributedCost += cost.AttributedCost
+		total.EnvelopeCount += cost.EnvelopeCount
+		if cost.WindowStart.Before(total.WindowStart) {
+			total.WindowStart = cost.WindowStart
+		}
+		if cost.WindowEnd.After(total.WindowEnd) {
+			total.WindowEnd = cost.WindowEnd
+		}
+	}
+
+	a.mutex.Lock()
+	for i := range a.closed {
+		add(&a.closed[i])
+	}
+	for _, window := range a.open {
+		for _, cost := range window {
+			add(cost)
+		}
+	}
+	a.mutex.Unlock()
+
+	result := make([]parser.CounterpartyCost, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sortByCost(result)
+	return result
+}
+
+func sortByCost(costs []parser.CounterpartyCost) {
+	sort.Slice(costs, func(i, j int) bool {
+		if costs[i].AttributedCost != costs[j].AttributedCost {
+			return costs[i].AttributedCost > costs[j].AttributedCost
+		}
+		return costs[i].Counterparty < costs[j].Counterparty
+	})
+}
@@ -0,0 +1,114 @@
+package attribution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters/exporterstest"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func TestAttribution(t *testing.T) {
+	now := time.Date(2025, 12, 3, 17, 5, 0, 0, time.UTC)
+	mediator := parser.Recipient{Type: "MediatorGroupRecipient"}
+	alice := parser.Recipient{Type: "MemberRecipient", Member: "PAR::alice::1220"}
+	bob := parser.Recipient{Type: "MemberRecipient", Member: "PAR::bob::1220"}
+	line := &parser.Line{
+		Timestamp: now,
+		Kind:      parser.KindEventCost,
+		CostDetails: &parser.EventCostDetails{
+			EventCost: 1169,
+			EnvelopesCost: []parser.EnvelopeCostDetails{
+				{WriteCost: 1017, ReadCost: 5, FinalCost: 1022, Recipients: []parser.Recipient{mediator}},
+				{WriteCost: 146, ReadCost: 1, FinalCost: 147, Recipients: []parser.Recipient{alice, bob, mediator}},
+			},
+		},
+	}
+
+	expected := map[Policy]map[string]float64{
+		PolicyEqual:    {mediator.ID(): 1022 + 49, alice.ID(): 49, bob.ID(): 49},
+		PolicyFull:     {mediator.ID(): 1022 + 147, alice.ID(): 147, bob.ID(): 147},
+		PolicyReadCost: {mediator.ID(): 5 + 1, alice.ID(): 1, bob.ID(): 1},
+	}
+
+	for policy, costs := range expected {
+		capture := &exporterstest.Capture{}
+		attributor := New(policy, time.Hour, 24*time.Hour, capture)
+		attributor.now = func() time.Time { return now }
+		_ = attributor.Export(context.Background(), line)
+
+		totals := attributor.Query(now.Add(-time.Hour), now.Add(time.Hour))
+		if len(totals) != len(costs) {
+			t.Fatalf("%s: counterparty count mismatch: got %d, want %d", policy, len(totals), len(costs))
+		}
+		if totals[0].Counterparty != mediator.ID() {
+			t.Errorf("%s: most expensive counterparty mismatch: got %s", policy, totals[0].Counterparty)
+		}
+		for _, total := range totals {
+			if total.AttributedCost != costs[total.Counterparty] {
+				t.Errorf("%s: %s cost mismatch: got %f, want %f", policy, total.Counterparty, total.AttributedCost, costs[total.Counterparty])
+			}
+		}
+
+		attributor.now = func() time.Time { return now.Add(time.Hour) }
+		attributor.flush(context.Background(), false)
+		if len(capture.Lines) != len(costs) {
+			t.Errorf("%s: exported line count mismatch: got %d, want %d", policy, len(capture.Lines), len(costs))
+		}
+	}
+}
+
+func TestAttributionDropsLateLines(t *testing.T) {
+	now := time.Date(2025, 12, 3, 17, 30, 0, 0, time.UTC)
+	alice := parser.Recipient{Type: "MemberRecipient", Member: "PAR::alice::1220"}
+	line := func(timestamp time.Time) *parser.Line {
+		return &parser.Line{
+			Timestamp: timestamp,
+			Kind:      parser.KindEventCost,
+			CostDetails: &parser.EventCostDetails{
+				EventCost:     100,
+				EnvelopesCost: []parser.EnvelopeCostDetails{{FinalCost: 100, Recipients: []parser.Recipient{alice}}},
+			},
+		}
+	}
+
+	capture := &exporterstest.Capture{}
+	attributor := New(PolicyFull, time.Hour, 24*time.Hour, capture)
+	attributor.AllowedLateness = time.Minute
+	attributor.now = func() time.Time { return now }
+	ctx := context.Background()
+	_ = attributor.Export(ctx, line(now))
+
+	// The window [17:00, 18:00) is still open within the allowed lateness
+	now = time.Date(2025, 12, 3, 18, 0, 30, 0, time.UTC)
+	attributor.flush(ctx, false)
+	if len(capture.Lines) != 0 {
+		t.Fatalf("exported line count mismatch: got %d, want 0", len(capture.Lines))
+	}
+	_ = attributor.Export(ctx, line(time.Date(2025, 12, 3, 17, 45, 0, 0, time.UTC)))
+
+	now = time.Date(2025, 12, 3, 18, 1, 30, 0, time.UTC)
+	attributor.flush(ctx, false)
+	_ = attributor.Export(ctx, line(time.Date(2025, 12, 3, 17, 50, 0, 0, time.UTC)))
+	attributor.flush(ctx, true)
+	if len(capture.Lines) != 1 {
+		t.Fatalf("exported line count mismatch: got %d, want 1", len(capture.Lines))
+	}
+	if cost := capture.Lines[0].CounterpartyCost.AttributedCost; cost != 200 {
+		t.Errorf("attributed cost mismatch: got %f, want 200", cost)
+	}
+
+	// A window which was flushed before its end is closed too
+	_ = attributor.Export(ctx, line(time.Date(2025, 12, 3, 18, 1, 0, 0, time.UTC)))
+	attributor.flush(ctx, true)
+	attributor.flush(ctx, false)
+	_ = attributor.Export(ctx, line(time.Date(2025, 12, 3, 18, 2, 0, 0, time.UTC)))
+	attributor.flush(ctx, true)
+	if len(capture.Lines) != 2 {
+		t.Errorf("exported line count mismatch: got %d, want 2", len(capture.Lines))
+	}
+	if late := attributor.Late(); late != 2 {
+		t.Errorf("late line count mismatch: got %d, want 2", late)
+	}
+}
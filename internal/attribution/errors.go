@@ -0,0 +1,7 @@
+package attribution
+
+import "errors"
+
+var (
+	ErrUnknownPolicy = errors.New("unknown attribution policy")
+)
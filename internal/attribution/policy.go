@@ -0,0 +1,42 @@
+package attribution
+
+import (
+	"fmt"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+// Policy defines how the cost of an envelope is split across its recipients
+type Policy string
+
+const (
+	// PolicyEqual splits the final cost of the envelope equally across the recipients
+	PolicyEqual Policy = "equal"
+	// PolicyFull attributes the full final cost of the envelope to every recipient
+	PolicyFull Policy = "full"
+	// PolicyReadCost attributes only the read cost of the envelope to every recipient
+	PolicyReadCost Policy = "read"
+)
+
+func ParsePolicy(s string) (Policy, error) {
+	switch Policy(s) {
+	case PolicyEqual, PolicyFull, PolicyReadCost:
+		return Policy(s), nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownPolicy, s)
+}
+
+// Share returns the cost attributed to each recipient of the envelope
+func (p Policy) Share(envelope parser.EnvelopeCostDetails) float64 {
+	if len(envelope.Recipients) == 0 {
+		return 0
+	}
+	switch p {
+	case PolicyFull:
+		return float64(envelope.FinalCost)
+	case PolicyReadCost:
+		return float64(envelope.ReadCost)
+	default:
+		return float64(envelope.FinalCost) / float64(len(envelope.Recipients))
+	}
+}
@@ -0,0 +1,85 @@
+package budget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Usage is the traffic spent by a participant in the current budget period
+type Usage struct {
+	Participant string    `json:"participant"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Limit       int       `json:"limit"`
+	Used        int       `json:"used"`
+	UsedPercent float64   `json:"used_percent"`
+	// BurnRate is the average traffic spent per hour since the beginning of the period
+	BurnRate float64 `json:"burn_rate_per_hour"`
+	// ProjectedExhaustion is when the budget runs out at the current burn rate,
+	// unset if it lasts until the end of the period
+	ProjectedExhaustion *time.Time `json:"projected_exhaustion,omitempty"`
+}
+
+// Alert is sent when the usage of a participant crosses one of the thresholds of its budget
+type Alert struct {
+	Usage
+	Threshold int `json:"threshold_percent"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, alert *Alert) error
+}
+
+var _ Notifier = (*Webhook)(nil)
+
+// Webhook posts the alerts as JSON to a generic webhook endpoint
+type Webhook struct {
+	URL                 string `json:"url"`
+	AuthorizationHeader string `json:"authorization_header"`
+	// Client sends the requests, it should have a timeout so that a hung
+	// webhook does not hold the alert forever
+	Client *http.Client `json:"-"`
+}
+
+func NewWebhook(url string, authHeader string) *Webhook {
+	return &Webhook{
+		URL:                 url,
+		AuthorizationHeader: authHeader,
+		Client:              &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (w *Webhook) Notify(ctx context.Context, alert *Alert) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	if w.AuthorizationHeader != "" {
+		req.Header.Add("Authorization", w.AuthorizationHeader)
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("failed to send budget alert, status code: " + resp.Status)
+	}
+
+	return nil
+}
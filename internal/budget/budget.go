@@ -0,0 +1,219 @@
+package budget
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Tracker)(nil)
+
+// Tracker tracks the event costs of every participant against its traffic budget
+// per period, and notifies when the usage crosses the alert thresholds.
+type Tracker struct {
+	Period time.Duration `json:"period"`
+	// DefaultLimit is the budget of the participants without an explicit one, 0 means unlimited
+	DefaultLimit int            `json:"default_limit"`
+	Limits       map[string]int `json:"limits"`
+	// Thresholds are the percentages of the budget which trigger an alert
+	Thresholds []int `json:"thresholds"`
+	// NotifyTimeout bounds the notification of an alert, 10s by default
+	NotifyTimeout time.Duration `json:"notify_timeout"`
+
+	notifier Notifier
+	usage    map[string]*participantUsage
+	mutex    *sync.Mutex
+	now      func() time.Time
+	// notifying tracks the alerts being sent, closing cancels them once Close
+	// gives up waiting
+	notifying *sync.WaitGroup
+	closing   context.Context
+	cancel    context.CancelFunc
+}
+
+type participantUsage struct {
+	periodStart time.Time
+	used        int
+	// alerted is the highest threshold already notified in the period
+	alerted int
+}
+
+func New(period time.Duration, defaultLimit int, limits map[string]int, thresholds []int, notifier Notifier) *Tracker {
+	thresholds = slices.Clone(thresholds)
+	slices.Sort(thresholds)
+	closing, cancel := context.WithCancel(context.Background())
+	return &Tracker{
+		Period:        period,
+		DefaultLimit:  defaultLimit,
+		Limits:        limits,
+		Thresholds:    thresholds,
+		NotifyTimeout: 10 * time.Second,
+		notifier:      notifier,
+		usage:         make(map[string]*participantUsage),
+		mutex:         &sync.Mutex{},
+		now:           time.Now,
+		notifying:     &sync.WaitGroup{},
+		closing:       closing,
+		cancel:        cancel,
+	}
+}
+
+// Start does nothing, the alerts are sent as the thresholds are crossed
+func (t *Tracker) Start(ctx context.Context) error {
+	return nil
+}
+
+// Flush waits for the alerts being sent
+func (t *Tracker) Flush(ctx context.Context) error {
+	return t.wait(ctx)
+}
+
+// Close waits for the alerts being sent, and cancels them if ctx is done first
+func (t *Tracker) Close(ctx context.Context) error {
+	err := t.wait(ctx)
+	t.cancel()
+	return err
+}
+
+func (t *Tracker) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.notifying.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Export adds the cost of the EventCost line to the usage of the participant, other lines are ignored
+func (t *Tracker) Export(ctx context.Context, line *parser.Line) error {
+	if line.Kind != parser.KindEventCost {
+		return nil
+	}
+	limit := t.limit(line.Participant)
+	if limit <= 0 {
+		return nil
+	}
+
+	timestamp := line.Timestamp
+	if timestamp.IsZero() {
+		timestamp = t.now()
+	}
+	periodStart := timestamp.Truncate(t.Period)
+
+	t.mutex.Lock()
+	u, ok := t.usage[line.Participant]
+	if !ok || periodStart.After(u.periodStart) {
+		u = &participantUsage{periodStart: periodStart}
+		t.usage[line.Participant] = u
+	}
+	if periodStart.Before(u.periodStart) {
+		// The line belongs to a previous period which is already over
+		t.mutex.Unlock()
+		return nil
+	}
+	u.used += line.CostDetails.EventCost
+
+	// A line which crosses several thresholds alerts for each of them, the
+	// lowest first
+	var alerts []*Alert
+	for _, threshold := range t.Thresholds {
+		if threshold > u.alerted && u.used*100 >= threshold*limit {
+			alerts = append(alerts, &Alert{
+				Usage:     t.current(line.Participant, u, limit, timestamp),
+				Threshold: threshold,
+			})
+			u.alerted = threshold
+		}
+	}
+	t.mutex.Unlock()
+
+	for _, alert := range alerts {
+		slog.WarnContext(ctx, "Traffic budget threshold crossed",
+			slog.String("participant", alert.Participant),
+			slog.Int("threshold_percent", alert.Threshold),
+			slog.Int("used", alert.Used),
+			slog.Int("limit", alert.Limit),
+		)
+	}
+	if len(alerts) > 0 && t.notifier != nil {
+		// Do not hold up the pipeline while the webhook responds. The alerts
+		// outlive the line, but not NotifyTimeout nor Close. They are sent one
+		// after the other, so that they arrive in order.
+		t.notifying.Go(func() {
+			for _, alert := range alerts {
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), t.NotifyTimeout)
+				stop := context.AfterFunc(t.closing, cancel)
+				if err := t.notifier.Notify(ctx, alert); err != nil {
+					slog.ErrorContext(ctx, "Failed to send budget alert", slog.Any("error", err))
+				}
+				stop()
+				cancel()
+			}
+		})
+	}
+
+	return nil
+}
+
+// Usages returns the current usage of every tracked participant
+func (t *Tracker) Usages() []Usage {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	usages := make([]Usage, 0, len(t.usage))
+	for participant, u := range t.usage {
+		usages = append(usages, t.current(participant, u, t.limit(participant), now))
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Participant < usages[j].Participant
+	})
+	return usages
+}
+
+func (t *Tracker) limit(participant string) int {
+	if limit, ok := t.Limits[participant]; ok {
+		return limit
+	}
+	return t.DefaultLimit
+}
+
+// current computes the usage with the burn rate projection at now
+func (t *Tracker) current(participant string, u *participantUsage, limit int, now time.Time) Usage {
+	periodEnd := u.periodStart.Add(t.Period)
+	status := Usage{
+		Participant: participant,
+		PeriodStart: u.periodStart,
+		PeriodEnd:   periodEnd,
+		Limit:       limit,
+		Used:        u.used,
+		UsedPercent: float64(u.used) * 100 / float64(limit),
+	}
+
+	elapsed := now.Sub(u.periodStart)
+	if elapsed <= 0 || u.used == 0 {
+		return status
+	}
+	status.BurnRate = float64(u.used) / elapsed.Hours()
+
+	remaining := limit - u.used
+	exhaustion := now
+	if remaining > 0 {
+		exhaustion = now.Add(time.Duration(float64(remaining) / status.BurnRate * float64(time.Hour)))
+	}
+	if exhaustion.Before(periodEnd) {
+		status.ProjectedExhaustion = &exhaustion
+	}
+	return status
+}
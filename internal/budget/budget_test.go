@@ -0,0 +1,136 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+type captureNotifier struct {
+	mutex  sync.Mutex
+	alerts []*Alert
+	done   chan struct{}
+}
+
+func (c *captureNotifier) Notify(ctx context.Context, alert *Alert) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.alerts = append(c.alerts, alert)
+	c.done <- struct{}{}
+	return nil
+}
+
+func TestTracker(t *testing.T) {
+	notifier := &captureNotifier{done: make(chan struct{}, 10)}
+	tracker := New(24*time.Hour, 10000, map[string]int{"other": 0}, []int{80, 50}, notifier)
+	periodStart := time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC)
+	now := periodStart.Add(6 * time.Hour)
+	tracker.now = func() time.Time { return now }
+
+	export := func(participant string, cost int) {
+		_ = tracker.Export(context.Background(), &parser.Line{
+			Timestamp:   now,
+			Participant: participant,
+			Kind:        parser.KindEventCost,
+			CostDetails: &parser.EventCostDetails{EventCost: cost},
+		})
+	}
+
+	export("participant", 4000)
+	export("other", 100000)
+	if len(notifier.done) != 0 {
+		t.Fatalf("No alert expected below the thresholds")
+	}
+
+	// 6000 used in 6 hours crosses 50%, and runs out in 4 more hours at 1000 per hour
+	export("participant", 2000)
+	<-notifier.done
+	alert := notifier.alerts[0]
+	if alert.Threshold != 50 || alert.Used != 6000 || alert.BurnRate != 1000 {
+		t.Errorf("Alert mismatch: got %+v", alert)
+	}
+	if alert.ProjectedExhaustion == nil || !alert.ProjectedExhaustion.Equal(now.Add(4*time.Hour)) {
+		t.Errorf("ProjectedExhaustion mismatch: got %v, want %v", alert.ProjectedExhaustion, now.Add(4*time.Hour))
+	}
+
+	export("participant", 5000)
+	<-notifier.done
+	if notifier.alerts[1].Threshold != 80 {
+		t.Errorf("Threshold mismatch: got %d, want 80", notifier.alerts[1].Threshold)
+	}
+
+	usages := tracker.Usages()
+	if len(usages) != 1 || usages[0].Used != 11000 {
+		t.Errorf("Usages mismatch: got %+v", usages)
+	}
+}
+
+func TestTrackerAlertsEveryCrossedThreshold(t *testing.T) {
+	notifier := &captureNotifier{done: make(chan struct{}, 10)}
+	tracker := New(24*time.Hour, 10000, nil, []int{80, 50, 100}, notifier)
+	now := time.Date(2025, 12, 3, 6, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	// A single event crosses 50% and 80%
+	_ = tracker.Export(context.Background(), &parser.Line{
+		Timestamp:   now,
+		Participant: "participant",
+		Kind:        parser.KindEventCost,
+		CostDetails: &parser.EventCostDetails{EventCost: 9000},
+	})
+	<-notifier.done
+	<-notifier.done
+	if err := tracker.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	if len(notifier.alerts) != 2 {
+		t.Fatalf("Alert count mismatch: got %d, want 2", len(notifier.alerts))
+	}
+	for i, threshold := range []int{50, 80} {
+		if notifier.alerts[i].Threshold != threshold || notifier.alerts[i].Used != 9000 {
+			t.Errorf("Alert %d mismatch: got %+v, want threshold %d", i, notifier.alerts[i], threshold)
+		}
+	}
+}
+
+type hungNotifier struct {
+	cancelled chan error
+}
+
+func (h *hungNotifier) Notify(ctx context.Context, alert *Alert) error {
+	<-ctx.Done()
+	h.cancelled <- ctx.Err()
+	return ctx.Err()
+}
+
+func TestTrackerCloseCancelsHungAlerts(t *testing.T) {
+	notifier := &hungNotifier{cancelled: make(chan error, 1)}
+	tracker := New(24*time.Hour, 100, nil, []int{50}, notifier)
+	_ = tracker.Export(context.Background(), &parser.Line{
+		Participant: "participant",
+		Kind:        parser.KindEventCost,
+		CostDetails: &parser.EventCostDetails{EventCost: 60},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tracker.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close() error = %v, want the deadline of the hung alert", err)
+	}
+	select {
+	case err := <-notifier.cancelled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("alert context error = %v, want it cancelled by Close", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the hung alert was not cancelled by Close")
+	}
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Errorf("Flush() error = %v, want the alerts drained", err)
+	}
+}
@@ -2,52 +2,179 @@ package catcher
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"io"
 	"log/slog"
-	"strings"
+	"time"
 
-	"github.com/DLC-link/cantcost/internal/env"
+	"github.com/DLC-link/cantcost/internal/config"
 	slogcontext "github.com/PumpkinSeed/slog-context"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
-func Stream(ctx context.Context, lineHandler func(context.Context, string) error) error {
+// readBufferSize fits most of the log lines, the longer ones are collected in a reused buffer
+const readBufferSize = 64 * 1024
+
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+// Observer is told about the state of the log stream of a target, which is
+// identified by its String. loggedAt is the Docker timestamp of the line, zero
+// if it has none. PodPhaseChanged is called while the stream is attached, when
+// the pod starts or stops Running.
+type Observer interface {
+	Attached(target string, podRunning bool)
+	Detached(target string)
+	LineRead(target string, loggedAt time.Time)
+	PodPhaseChanged(target string, podRunning bool)
+}
+
+// Observers tells every observer
+type Observers []Observer
+
+func (o Observers) Attached(target string, podRunning bool) {
+	for _, observer := range o {
+		observer.Attached(target, podRunning)
+	}
+}
+
+func (o Observers) Detached(target string) {
+	for _, observer := range o {
+		observer.Detached(target)
+	}
+}
+
+func (o Observers) LineRead(target string, loggedAt time.Time) {
+	for _, observer := range o {
+		observer.LineRead(target, loggedAt)
+	}
+}
+
+func (o Observers) PodPhaseChanged(target string, podRunning bool) {
+	for _, observer := range o {
+		observer.PodPhaseChanged(target, podRunning)
+	}
+}
+
+// Follow streams the logs of the target like Stream, and reopens the stream
+// with an exponential backoff when it ends or fails, e.g. when the pod is
+// replaced, until the context is done. The lines which were read already are
+// skipped after a reconnection.
+func Follow(ctx context.Context, target config.Target, observer Observer, lineHandler func(context.Context, []byte) error) {
+	var since time.Time
+	delay := minReconnectDelay
+	for {
+		openedAt := time.Now()
+		err := stream(ctx, target, observer, &since, lineHandler)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(openedAt) > maxReconnectDelay {
+			// The stream was healthy for a while, this is a new failure
+			delay = minReconnectDelay
+		}
+		slog.WarnContext(ctx, "Log stream interrupted, reconnecting",
+			slog.String("target", target.String()),
+			slog.Duration("delay", delay),
+			slog.Any("error", err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// Stream follows the logs of the target deployment's pod and calls lineHandler
+// with every line, without the trailing newline. The line is backed by a reused
+// buffer, so it is only valid until lineHandler returns.
+func Stream(ctx context.Context, target config.Target, observer Observer, lineHandler func(context.Context, []byte) error) error {
+	return stream(ctx, target, observer, &time.Time{}, lineHandler)
+}
+
+// stream is Stream which skips the lines logged at or before since, if it is
+// set, and keeps since at the timestamp of the last line read
+func stream(ctx context.Context, target config.Target, observer Observer, since *time.Time, lineHandler func(context.Context, []byte) error) error {
 	clientSet, err := getKubernetesClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	ctx = slogcontext.WithValue(ctx, "target_deployment", env.GetTargetDeployment())
-	ctx = slogcontext.WithValue(ctx, "target_namespace", env.GetTargetNamespace())
+	ctx = slogcontext.WithValue(ctx, "target_deployment", target.Deployment)
+	ctx = slogcontext.WithValue(ctx, "target_namespace", target.Namespace)
 
-	pod, err := getPod(ctx, clientSet)
+	pod, err := getPod(ctx, clientSet, target)
 	if err != nil {
 		return err
 	}
 
-	stream, err := getLogStream(ctx, clientSet, pod)
+	logStream, err := getLogStream(ctx, clientSet, target, pod, *since)
 	if err != nil {
 		return err
 	}
-	defer stream.Close()
+	defer logStream.Close()
+	name := target.String()
+	observer.Attached(name, pod.Status.Phase == corev1.PodRunning)
+	defer observer.Detached(name)
+
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	watching := make(chan struct{})
+	go func() {
+		defer close(watching)
+		watchPhase(watchCtx, clientSet, target, pod, observer)
+	}()
+	defer func() {
+		stopWatch()
+		<-watching
+	}()
 
-	r := bufio.NewReader(stream)
+	skipUntil := *since
+	r := bufio.NewReaderSize(logStream, readBufferSize)
+	// buf collects the lines which are longer than the reader's buffer
+	var buf []byte
 
 	for {
-		line, err := r.ReadString('\n') // keeps reading until newline
+		line, err := r.ReadSlice('\n') // keeps reading until newline
+		if err == bufio.ErrBufferFull {
+			buf = append(buf, line...)
+			continue
+		}
+		if len(buf) > 0 {
+			buf = append(buf, line...)
+			line = buf
+		}
 		if len(line) > 0 {
 			// trim trailing newline(s) to match Scanner behavior
-			line = strings.TrimRight(line, "\r\n")
-			if err2 := lineHandler(ctx, line); err2 != nil {
-				slog.ErrorContext(ctx, "Error handling log line", slog.Any("error", err2))
+			line = bytes.TrimRight(line, "\r\n")
+			loggedAt := dockerTimestamp(line)
+			if skipUntil.IsZero() || loggedAt.After(skipUntil) {
+				if !loggedAt.IsZero() {
+					*since = loggedAt
+				}
+				observer.LineRead(name, loggedAt)
+				if err2 := lineHandler(ctx, line); err2 != nil {
+					slog.ErrorContext(ctx, "Error handling log line", slog.Any("error", err2))
+				}
 			}
 		}
+		buf = buf[:0]
 
 		if err != nil {
+			if ctx.Err() != nil {
+				slog.InfoContext(ctx, "Log stream stopped")
+				return nil
+			}
 			if err == io.EOF {
 				// if your log stream is truly "follow", EOF usually means stream ended
 				slog.InfoContext(ctx, "Log stream ended")
@@ -59,14 +186,27 @@ func Stream(ctx context.Context, lineHandler func(context.Context, string) error
 	}
 }
 
+// dockerTimestamp returns the timestamp which prefixes the line, zero if there is none
+func dockerTimestamp(line []byte) time.Time {
+	prefix, _, ok := bytes.Cut(line, []byte{' '})
+	if !ok {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, string(prefix))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 func getKubernetesClient(ctx context.Context) (*kubernetes.Clientset, error) {
-	config, err := rest.InClusterConfig()
+	restConfig, err := rest.InClusterConfig()
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to create in-cluster config", slog.Any("error", err))
 		return nil, err
 	}
 
-	clientSet, err := kubernetes.NewForConfig(config)
+	clientSet, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to create clientSet", slog.Any("error", err))
 		return nil, err
@@ -86,11 +226,11 @@ func selectFirstRunningPod(list *corev1.PodList) corev1.Pod {
 	return list.Items[0]
 }
 
-func getPod(ctx context.Context, clientSet *kubernetes.Clientset) (corev1.Pod, error) {
+func getPod(ctx context.Context, clientSet *kubernetes.Clientset, target config.Target) (corev1.Pod, error) {
 	// 1) Get the Deployment
 	deploy, err := clientSet.AppsV1().
-		Deployments(env.GetTargetNamespace()).
-		Get(ctx, env.GetTargetDeployment(), metav1.GetOptions{})
+		Deployments(target.Namespace).
+		Get(ctx, target.Deployment, metav1.GetOptions{})
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to get deployment", slog.Any("error", err))
 		return corev1.Pod{}, err
@@ -106,7 +246,7 @@ func getPod(ctx context.Context, clientSet *kubernetes.Clientset) (corev1.Pod, e
 
 	// 3) List pods matching the selector
 	podList, err := clientSet.CoreV1().
-		Pods(env.GetTargetNamespace()).
+		Pods(target.Namespace).
 		List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to list pods", slog.Any("error", err))
@@ -125,18 +265,71 @@ func getPod(ctx context.Context, clientSet *kubernetes.Clientset) (corev1.Pod, e
 	return pod, nil
 }
 
-func getLogStream(ctx context.Context, clientSet *kubernetes.Clientset, pod corev1.Pod) (io.ReadCloser, error) {
+// watchPhase tells the observer when the pod starts or stops Running, until the
+// context is done. The watch is reopened when the API server ends it.
+func watchPhase(ctx context.Context, clientSet *kubernetes.Clientset, target config.Target, pod corev1.Pod, observer Observer) {
+	name := target.String()
+	running := pod.Status.Phase == corev1.PodRunning
+	resourceVersion := pod.ResourceVersion
+	for {
+		watcher, err := clientSet.CoreV1().
+			Pods(target.Namespace).
+			Watch(ctx, metav1.ListOptions{
+				FieldSelector:   fields.OneTermEqualSelector("metadata.name", pod.Name).String(),
+				ResourceVersion: resourceVersion,
+			})
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to watch pod phase",
+				slog.String("pod_name", pod.Name), slog.Any("error", err))
+		} else {
+			for event := range watcher.ResultChan() {
+				if event.Type == watch.Error {
+					// The resource version is too old, start again from the current state
+					resourceVersion = ""
+					continue
+				}
+				p, ok := event.Object.(*corev1.Pod)
+				if !ok {
+					continue
+				}
+				resourceVersion = p.ResourceVersion
+				if event.Type == watch.Deleted {
+					p.Status.Phase = corev1.PodUnknown
+				}
+				if now := p.Status.Phase == corev1.PodRunning; now != running {
+					running = now
+					observer.PodPhaseChanged(name, running)
+				}
+			}
+			watcher.Stop()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(minReconnectDelay):
+		}
+	}
+}
+
+func getLogStream(ctx context.Context, clientSet *kubernetes.Clientset, target config.Target, pod corev1.Pod, since time.Time) (io.ReadCloser, error) {
 	// Stream logs from the chosen pod
 	logOptions := &corev1.PodLogOptions{
 		Follow:     true,
 		Timestamps: true,
 	}
-	if env.GetTargetContainer() != "" {
-		logOptions.Container = env.GetTargetContainer()
+	if !since.IsZero() {
+		// SinceTime has a precision of a second, the lines of that second which
+		// were read already are skipped by the caller
+		sinceTime := metav1.NewTime(since)
+		logOptions.SinceTime = &sinceTime
+	}
+	if target.Container != "" {
+		logOptions.Container = target.Container
 	}
 
 	req := clientSet.CoreV1().
-		Pods(env.GetTargetNamespace()).
+		Pods(target.Namespace).
 		GetLogs(pod.Name, logOptions)
 
 	stream, err := req.Stream(ctx)
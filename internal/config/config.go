@@ -0,0 +1,439 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/parser"
+	"sigs.k8s.io/yaml"
+)
+
+// FileEnv is the environment variable which points to the configuration file
+const FileEnv = "CONFIG_FILE"
+
+// Config is the whole cantcost configuration. It is loaded from a YAML or JSON
+// file, then the environment variables override it, see Load.
+type Config struct {
+	LogLevel       string `json:"log_level"`
+	IncludeMessage bool   `json:"include_message"`
+	// ShutdownGracePeriod bounds the flush of the exporters on shutdown
+	ShutdownGracePeriod Duration `json:"shutdown_grace_period"`
+
+	Targets   []Target   `json:"targets"`
+	Exporters []Exporter `json:"exporters"`
+
+	Validation       Validation       `json:"validation"`
+	DeadLetter       DeadLetter       `json:"dead_letter"`
+	TraceAggregation TraceAggregation `json:"trace_aggregation"`
+	Attribution      Attribution      `json:"attribution"`
+	Rollup           Rollup           `json:"rollup"`
+	Budget           Budget           `json:"budget"`
+	Pricing          Pricing          `json:"pricing"`
+	Anomaly          Anomaly          `json:"anomaly"`
+	API              API              `json:"api"`
+	Store            Store            `json:"store"`
+	Stream           Stream           `json:"stream"`
+	Correlation      Correlation      `json:"correlation"`
+	Dashboard        Dashboard        `json:"dashboard"`
+	Health           Health           `json:"health"`
+	Metrics          Metrics          `json:"metrics"`
+}
+
+// Target is a deployment whose pod logs are followed
+type Target struct {
+	Namespace  string `json:"namespace"`
+	Deployment string `json:"deployment"`
+	// Container is the container of the pod to follow, the default one if empty
+	Container string `json:"container"`
+}
+
+// String identifies the target in the logs and the health checks
+func (t Target) String() string {
+	if t.Container != "" {
+		return t.Namespace + "/" + t.Deployment + "/" + t.Container
+	}
+	return t.Namespace + "/" + t.Deployment
+}
+
+type Exporter struct {
+	// Name identifies the exporter in the logs and the statistics, <type>-<index> by default
+	Name string `json:"name"`
+	// Type is http or file
+	Type string `json:"type"`
+	// URL may be a text/template rendered for every line, e.g.
+	// https://example.com/{{ .Synchronizer }}/costs
+	URL        string `json:"url"`
+	AuthHeader string `json:"auth_header"`
+	BatchSize  int    `json:"batch_size"`
+	Path       string `json:"path"`
+	// Timeout bounds the export of a line, 10s by default
+	Timeout Duration `json:"timeout"`
+	// FlushInterval is how often a partial HTTP batch is posted, 5s by default
+	FlushInterval Duration `json:"flush_interval"`
+	// SigningSecret enables the HMAC-SHA256 signing of the HTTP requests, it can
+	// be read from SigningSecretFile instead, e.g. a mounted Kubernetes secret
+	SigningSecret     string `json:"signing_secret"`
+	SigningSecretFile string `json:"signing_secret_file"`
+	// Encoding is the body format of the HTTP requests, json, jsonl, cbor or protobuf
+	Encoding string `json:"encoding"`
+	// ContentEncoding compresses the body of the HTTP requests, gzip or zstd
+	ContentEncoding string `json:"content_encoding"`
+	// OAuth2 authenticates the HTTP requests with client-credentials tokens,
+	// instead of AuthHeader
+	OAuth2 OAuth2 `json:"oauth2"`
+	// TLS sets the client certificate and the CA bundle of the HTTP requests
+	TLS TLS `json:"tls"`
+	// Method is the HTTP method, POST by default. Like URL and the values of
+	// Headers, it may be a template rendered for every line.
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	// BodyTemplate renders the body of a batch instead of the Encoding
+	BodyTemplate string `json:"body_template"`
+
+	// Filter selects the lines sent to the exporter, all of them if it is empty
+	Filter Filter `json:"filter"`
+	// Fields are the MessageLine fields sent to the exporter, all of them if it is empty
+	Fields []string `json:"fields"`
+}
+
+// Templated reports whether the HTTP requests are rendered with a RequestTemplate
+func (e *Exporter) Templated() bool {
+	return e.Method != "" || len(e.Headers) > 0 || e.BodyTemplate != "" || strings.Contains(e.URL, "{{")
+}
+
+// RequestTemplate parses the templates of the HTTP requests, it is nil if the
+// exporter is not templated
+func (e *Exporter) RequestTemplate() (*exporters.RequestTemplate, error) {
+	if !e.Templated() {
+		return nil, nil
+	}
+	return exporters.NewRequestTemplate(e.Method, e.URL, e.Headers, e.BodyTemplate)
+}
+
+// OAuth2 is the client-credentials flow, it is disabled if TokenURL is empty
+type OAuth2 struct {
+	TokenURL         string   `json:"token_url"`
+	ClientID         string   `json:"client_id"`
+	ClientSecret     string   `json:"client_secret"`
+	ClientSecretFile string   `json:"client_secret_file"`
+	Scopes           []string `json:"scopes"`
+	// RefreshBefore is how long before its expiry a token is renewed, 1m by default
+	RefreshBefore Duration `json:"refresh_before"`
+}
+
+func (o *OAuth2) Enabled() bool {
+	return o.TokenURL != ""
+}
+
+// TLS is the mutual TLS of the HTTP requests. The files are usually mounted
+// from a Kubernetes secret; they are reloaded when they change, checked every
+// ReloadInterval.
+type TLS struct {
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	// CAFile replaces the system CA bundle to verify the server
+	CAFile         string   `json:"ca_file"`
+	ReloadInterval Duration `json:"reload_interval"`
+}
+
+func (t *TLS) Enabled() bool {
+	return t.CertFile != "" || t.CAFile != ""
+}
+
+// Filter selects lines, see filter.Filter
+type Filter struct {
+	// MinCost is the minimum event cost, lines without cost details do not match it
+	MinCost      int      `json:"min_cost"`
+	Synchronizer string   `json:"synchronizer"`
+	SpanName     string   `json:"span_name"`
+	Level        string   `json:"level"`
+	Kinds        []string `json:"kinds"`
+}
+
+func (f *Filter) Empty() bool {
+	return f.MinCost == 0 && f.Synchronizer == "" && f.SpanName == "" && f.Level == "" && len(f.Kinds) == 0
+}
+
+func (f *Filter) Filter() filter.Filter {
+	kinds := make([]parser.Kind, len(f.Kinds))
+	for i, kind := range f.Kinds {
+		kinds[i] = parser.Kind(kind)
+	}
+	return filter.Filter{
+		MinCost:      f.MinCost,
+		Synchronizer: f.Synchronizer,
+		SpanName:     f.SpanName,
+		Level:        f.Level,
+		Kinds:        kinds,
+	}
+}
+
+type Validation struct {
+	Mode string `json:"mode"`
+}
+
+type DeadLetter struct {
+	// Type is file or http, the dead-letter sink is disabled if it is empty
+	Type       string `json:"type"`
+	File       string `json:"file"`
+	URL        string `json:"url"`
+	AuthHeader string `json:"auth_header"`
+}
+
+type TraceAggregation struct {
+	// Window is 0 when the per-trace aggregation is disabled
+	Window Duration `json:"window"`
+}
+
+type Attribution struct {
+	// Policy is empty when the attribution is disabled
+	Policy          string   `json:"policy"`
+	Window          Duration `json:"window"`
+	Retention       Duration `json:"retention"`
+	AllowedLateness Duration `json:"allowed_lateness"`
+}
+
+type Rollup struct {
+	// Windows is empty when the rollups are disabled
+	Windows         []Duration `json:"windows"`
+	AllowedLateness Duration   `json:"allowed_lateness"`
+}
+
+type Budget struct {
+	Period Duration `json:"period"`
+	// Limit is the budget of the participants without an explicit one, 0 means unlimited
+	Limit             int            `json:"limit"`
+	ParticipantLimits map[string]int `json:"participant_limits"`
+	AlertThresholds   []int          `json:"alert_thresholds"`
+	WebhookURL        string         `json:"webhook_url"`
+	WebhookAuthHeader string         `json:"webhook_auth_header"`
+	// WebhookTimeout bounds the request of an alert, 10s by default
+	WebhookTimeout Duration `json:"webhook_timeout"`
+	// WebhookTLS sets the client certificate and the CA bundle of the webhook requests
+	WebhookTLS TLS `json:"webhook_tls"`
+}
+
+type Pricing struct {
+	RatesFile string `json:"rates_file"`
+	// USDPerMB is 0 when the pricing is disabled, unless there is a rates file
+	USDPerMB float64 `json:"usd_per_mb"`
+	USDPerCC float64 `json:"usd_per_cc"`
+}
+
+type Anomaly struct {
+	// Method is empty when the anomaly detection is disabled
+	Method    string  `json:"method"`
+	Key       string  `json:"key"`
+	Threshold float64 `json:"threshold"`
+	Warmup    int     `json:"warmup"`
+	EWMAAlpha float64 `json:"ewma_alpha"`
+	// MaxKeys bounds the number of baselines, the least recently used are evicted
+	MaxKeys int `json:"max_keys"`
+}
+
+type API struct {
+	Addr string `json:"addr"`
+}
+
+type Store struct {
+	// Capacity is 0 when the query store is disabled
+	Capacity int      `json:"capacity"`
+	TTL      Duration `json:"ttl"`
+}
+
+type Stream struct {
+	BufferSize int `json:"buffer_size"`
+}
+
+type Correlation struct {
+	Enabled    bool     `json:"enabled"`
+	Wait       Duration `json:"wait"`
+	Retention  Duration `json:"retention"`
+	AuthHeader string   `json:"auth_header"`
+}
+
+type Dashboard struct {
+	Enabled bool     `json:"enabled"`
+	History Duration `json:"history"`
+	Refresh Duration `json:"refresh"`
+	// MaxTraces bounds the number of traces kept for the top traces
+	MaxTraces int `json:"max_traces"`
+}
+
+type Health struct {
+	// StaleAfter fails the liveness probe when no log line was read for that
+	// long while the target pod is Running, 0 disables it
+	StaleAfter Duration `json:"stale_after"`
+}
+
+type Metrics struct {
+	// SummaryInterval is how often the processing summary is logged, 0 disables it
+	SummaryInterval Duration `json:"summary_interval"`
+}
+
+// Default returns the configuration used for the settings which are neither in
+// the file nor in the environment
+func Default() Config {
+	return Config{
+		LogLevel:            "info",
+		ShutdownGracePeriod: Duration(20 * time.Second),
+		Validation: Validation{
+			Mode: "warn",
+		},
+		DeadLetter: DeadLetter{
+			File: "/tmp/cantcost-dead-letter.jsonl",
+		},
+		Attribution: Attribution{
+			Window:          Duration(time.Hour),
+			Retention:       Duration(7 * 24 * time.Hour),
+			AllowedLateness: Duration(30 * time.Second),
+		},
+		Rollup: Rollup{
+			AllowedLateness: Duration(30 * time.Second),
+		},
+		Budget: Budget{
+			Period:          Duration(24 * time.Hour),
+			AlertThresholds: []int{50, 80, 100},
+			WebhookTimeout:  Duration(10 * time.Second),
+			WebhookTLS:      TLS{ReloadInterval: Duration(30 * time.Second)},
+		},
+		Anomaly: Anomaly{
+			Key:       "span",
+			Threshold: 3,
+			Warmup:    30,
+			EWMAAlpha: 0.1,
+			MaxKeys:   10000,
+		},
+		API: API{
+			Addr: ":8080",
+		},
+		Store: Store{
+			Capacity: 10000,
+			TTL:      Duration(time.Hour),
+		},
+		Stream: Stream{
+			BufferSize: 256,
+		},
+		Correlation: Correlation{
+			Wait:      Duration(5 * time.Second),
+			Retention: Duration(10 * time.Minute),
+		},
+		Dashboard: Dashboard{
+			Enabled:   true,
+			History:   Duration(time.Hour),
+			Refresh:   Duration(5 * time.Second),
+			MaxTraces: 10000,
+		},
+		Health: Health{
+			StaleAfter: Duration(15 * time.Minute),
+		},
+		Metrics: Metrics{
+			SummaryInterval: Duration(time.Minute),
+		},
+	}
+}
+
+// Load reads the configuration file, if path is not empty, applies the
+// environment overrides and validates the result. All the problems are
+// reported together.
+func Load(path string) (*Config, error) {
+	config := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		// YAML is a superset of JSON, so both are accepted; unknown fields are errors
+		if err := yaml.UnmarshalStrict(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	// A variable which cannot be parsed leaves its field as it is, so the rest
+	// is still validated and reported along with it
+	envErr := config.applyEnv(os.LookupEnv)
+	config.setDefaults()
+	if err := errors.Join(envErr, config.Validate()); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// setDefaults fills the defaults of the list items, which Default cannot know
+func (c *Config) setDefaults() {
+	for i := range c.Targets {
+		if c.Targets[i].Namespace == "" {
+			c.Targets[i].Namespace = "default"
+		}
+	}
+	for i := range c.Exporters {
+		if c.Exporters[i].Name == "" {
+			c.Exporters[i].Name = fmt.Sprintf("%s-%d", c.Exporters[i].Type, i)
+		}
+		if c.Exporters[i].Timeout == 0 {
+			c.Exporters[i].Timeout = Duration(10 * time.Second)
+		}
+		if c.Exporters[i].Type == "http" && c.Exporters[i].BatchSize == 0 {
+			c.Exporters[i].BatchSize = 10
+		}
+		if c.Exporters[i].Type == "http" && c.Exporters[i].FlushInterval == 0 {
+			c.Exporters[i].FlushInterval = Duration(5 * time.Second)
+		}
+		if c.Exporters[i].OAuth2.RefreshBefore == 0 {
+			c.Exporters[i].OAuth2.RefreshBefore = Duration(time.Minute)
+		}
+		if c.Exporters[i].TLS.ReloadInterval == 0 {
+			c.Exporters[i].TLS.ReloadInterval = Duration(30 * time.Second)
+		}
+	}
+}
+
+// Redacted returns a copy of the configuration without the secrets, to be logged
+func (c *Config) Redacted() Config {
+	redacted := *c
+	redacted.Exporters = make([]Exporter, len(c.Exporters))
+	for i, exporter := range c.Exporters {
+		exporter.AuthHeader = redact(exporter.AuthHeader)
+		exporter.SigningSecret = redact(exporter.SigningSecret)
+		exporter.OAuth2.ClientSecret = redact(exporter.OAuth2.ClientSecret)
+		// The headers often carry API keys, e.g. DD-API-KEY
+		if exporter.Headers != nil {
+			headers := make(map[string]string, len(exporter.Headers))
+			for name, value := range exporter.Headers {
+				headers[name] = redact(value)
+			}
+			exporter.Headers = headers
+		}
+		redacted.Exporters[i] = exporter
+	}
+	redacted.DeadLetter.AuthHeader = redact(c.DeadLetter.AuthHeader)
+	redacted.Budget.WebhookAuthHeader = redact(c.Budget.WebhookAuthHeader)
+	redacted.Correlation.AuthHeader = redact(c.Correlation.AuthHeader)
+	return redacted
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "REDACTED"
+}
+
+// Level returns the slog level of LogLevel, which is validated already
+func (c *Config) Level() slog.Level {
+	switch c.LogLevel {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testFile = `
+log_level: debug
+targets:
+  - deployment: participant-1
+  - namespace: canton
+    deployment: participant-2
+exporters:
+  - type: http
+    url: https://billing.example.com/cantcost
+  - type: http
+    url: https://alerts.example.com/hook
+    batch_size: 1
+rollup:
+  windows: [1m, 1h]
+`
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("TARGET_CONTAINER", "participant")
+	t.Setenv("STORE_TTL", "10m")
+
+	config, err := Load(writeFile(t, testFile))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if config.LogLevel != "debug" {
+		t.Errorf("LogLevel = %q, want debug", config.LogLevel)
+	}
+	if len(config.Targets) != 2 || config.Targets[0].Namespace != "default" || config.Targets[0].Container != "participant" || config.Targets[1].Namespace != "canton" {
+		t.Errorf("Targets = %+v", config.Targets)
+	}
+	if len(config.Exporters) != 2 || config.Exporters[0].BatchSize != 10 || config.Exporters[1].BatchSize != 1 {
+		t.Errorf("Exporters = %+v", config.Exporters)
+	}
+	if windows := StdDurations(config.Rollup.Windows); len(windows) != 2 || windows[1] != time.Hour {
+		t.Errorf("Rollup.Windows = %v", windows)
+	}
+	if config.Store.TTL.Std() != 10*time.Minute {
+		t.Errorf("Store.TTL = %s, want the environment override", config.Store.TTL)
+	}
+	// Untouched settings keep their default
+	if config.Anomaly.Key != "span" || config.API.Addr != ":8080" {
+		t.Errorf("defaults lost: anomaly key %q, api addr %q", config.Anomaly.Key, config.API.Addr)
+	}
+}
+
+func TestLoadEnvOnly(t *testing.T) {
+	t.Setenv("TARGET_DEPLOYMENT", "participant")
+	t.Setenv("HTTP_EXPORTER_URL", "http://localhost:9000")
+
+	config, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(config.Targets) != 1 || config.Targets[0].Deployment != "participant" {
+		t.Errorf("Targets = %+v", config.Targets)
+	}
+	if len(config.Exporters) != 1 || config.Exporters[0].Type != "http" || config.Exporters[0].URL != "http://localhost:9000" {
+		t.Errorf("Exporters = %+v", config.Exporters)
+	}
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	t.Setenv("HTTP_EXPORTER_BATCH_SIZE", "ten")
+	t.Setenv("STORE_TTL", "forever")
+
+	_, err := Load("")
+	if err == nil {
+		t.Fatal("Load() succeeded with invalid environment variables")
+	}
+	for _, name := range []string{"HTTP_EXPORTER_BATCH_SIZE", "STORE_TTL"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error does not mention %s: %v", name, err)
+		}
+	}
+
+	path := writeFile(t, `
+exporters:
+  - type: kafka
+  - type: http
+    url: not a url
+  - type: http
+    url: https://example.com/{{ .Synchroniser }}
+validation:
+  mode: strict
+`)
+	t.Setenv("HTTP_EXPORTER_BATCH_SIZE", "")
+	t.Setenv("STORE_TTL", "")
+	_, err = Load(path)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Load() error = %v, want %v", err, ErrInvalidConfig)
+	}
+	for _, problem := range []string{"targets", "exporters[0].type", "exporters[1].url", "exporters[2]: invalid template", "validation.mode"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("error does not mention %s: %v", problem, err)
+		}
+	}
+}
+
+func TestLoadReportsEnvAndValidationErrorsTogether(t *testing.T) {
+	t.Setenv("STORE_TTL", "forever")
+	path := writeFile(t, `
+targets:
+  - deployment: participant
+exporters:
+  - type: kafka
+`)
+	_, err := Load(path)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Load() error = %v, want %v", err, ErrInvalidConfig)
+	}
+	for _, problem := range []string{"STORE_TTL", "exporters[0].type"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("error does not mention %s: %v", problem, err)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	path := writeFile(t, "targets:\n  - deployment: participant\n    namspace: canton\n")
+	if _, err := Load(path); err == nil {
+		t.Fatal("Load() accepted an unknown field")
+	}
+}
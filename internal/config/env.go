@@ -0,0 +1,253 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envOverride sets a setting from the value of its environment variable
+type envOverride struct {
+	name  string
+	apply func(c *Config, v string) error
+}
+
+// envOverrides are the environment variables which override the configuration
+// file. The target and exporter variables override the first target and
+// exporter, which are created if the file has none.
+var envOverrides = []envOverride{
+	{"LOG_LEVEL", stringVar(func(c *Config) *string { return &c.LogLevel })},
+	{"INCLUDE_MESSAGE", boolVar(func(c *Config) *bool { return &c.IncludeMessage })},
+	{"SHUTDOWN_GRACE_PERIOD", durationVar(func(c *Config) *Duration { return &c.ShutdownGracePeriod })},
+
+	{"TARGET_DEPLOYMENT", stringVar(func(c *Config) *string { return &c.firstTarget().Deployment })},
+	{"TARGET_CONTAINER", stringVar(func(c *Config) *string { return &c.firstTarget().Container })},
+	{"TARGET_NAMESPACE", stringVar(func(c *Config) *string { return &c.firstTarget().Namespace })},
+
+	{"EXPORTER_TYPE", stringVar(func(c *Config) *string { return &c.firstExporter().Type })},
+	{"HTTP_EXPORTER_URL", stringVar(func(c *Config) *string { return &c.firstExporter().URL })},
+	{"HTTP_EXPORTER_AUTH_HEADER", stringVar(func(c *Config) *string { return &c.firstExporter().AuthHeader })},
+	{"HTTP_EXPORTER_BATCH_SIZE", intVar(func(c *Config) *int { return &c.firstExporter().BatchSize })},
+	{"HTTP_EXPORTER_TIMEOUT", durationVar(func(c *Config) *Duration { return &c.firstExporter().Timeout })},
+	{"HTTP_EXPORTER_SIGNING_SECRET", stringVar(func(c *Config) *string { return &c.firstExporter().SigningSecret })},
+	{"HTTP_EXPORTER_SIGNING_SECRET_FILE", stringVar(func(c *Config) *string { return &c.firstExporter().SigningSecretFile })},
+	{"HTTP_EXPORTER_ENCODING", stringVar(func(c *Config) *string { return &c.firstExporter().Encoding })},
+	{"HTTP_EXPORTER_CONTENT_ENCODING", stringVar(func(c *Config) *string { return &c.firstExporter().ContentEncoding })},
+	{"HTTP_EXPORTER_OAUTH2_TOKEN_URL", stringVar(func(c *Config) *string { return &c.firstExporter().OAuth2.TokenURL })},
+	{"HTTP_EXPORTER_OAUTH2_CLIENT_ID", stringVar(func(c *Config) *string { return &c.firstExporter().OAuth2.ClientID })},
+	{"HTTP_EXPORTER_OAUTH2_CLIENT_SECRET", stringVar(func(c *Config) *string { return &c.firstExporter().OAuth2.ClientSecret })},
+	{"HTTP_EXPORTER_OAUTH2_CLIENT_SECRET_FILE", stringVar(func(c *Config) *string { return &c.firstExporter().OAuth2.ClientSecretFile })},
+	{"HTTP_EXPORTER_OAUTH2_SCOPES", stringListVar(func(c *Config) *[]string { return &c.firstExporter().OAuth2.Scopes })},
+	{"HTTP_EXPORTER_TLS_CERT_FILE", stringVar(func(c *Config) *string { return &c.firstExporter().TLS.CertFile })},
+	{"HTTP_EXPORTER_TLS_KEY_FILE", stringVar(func(c *Config) *string { return &c.firstExporter().TLS.KeyFile })},
+	{"HTTP_EXPORTER_TLS_CA_FILE", stringVar(func(c *Config) *string { return &c.firstExporter().TLS.CAFile })},
+	{"HTTP_EXPORTER_FLUSH_INTERVAL", durationVar(func(c *Config) *Duration { return &c.firstExporter().FlushInterval })},
+	{"HTTP_EXPORTER_METHOD", stringVar(func(c *Config) *string { return &c.firstExporter().Method })},
+	{"HTTP_EXPORTER_BODY_TEMPLATE", stringVar(func(c *Config) *string { return &c.firstExporter().BodyTemplate })},
+
+	{"COST_VALIDATION_MODE", stringVar(func(c *Config) *string { return &c.Validation.Mode })},
+
+	{"DEAD_LETTER_TYPE", stringVar(func(c *Config) *string { return &c.DeadLetter.Type })},
+	{"DEAD_LETTER_FILE", stringVar(func(c *Config) *string { return &c.DeadLetter.File })},
+	{"DEAD_LETTER_URL", stringVar(func(c *Config) *string { return &c.DeadLetter.URL })},
+	{"DEAD_LETTER_AUTH_HEADER", stringVar(func(c *Config) *string { return &c.DeadLetter.AuthHeader })},
+
+	{"TRACE_AGGREGATION_WINDOW", durationVar(func(c *Config) *Duration { return &c.TraceAggregation.Window })},
+
+	{"ATTRIBUTION_POLICY", stringVar(func(c *Config) *string { return &c.Attribution.Policy })},
+	{"ATTRIBUTION_WINDOW", durationVar(func(c *Config) *Duration { return &c.Attribution.Window })},
+	{"ATTRIBUTION_RETENTION", durationVar(func(c *Config) *Duration { return &c.Attribution.Retention })},
+	{"ATTRIBUTION_ALLOWED_LATENESS", durationVar(func(c *Config) *Duration { return &c.Attribution.AllowedLateness })},
+
+	{"ROLLUP_WINDOWS", durationListVar(func(c *Config) *[]Duration { return &c.Rollup.Windows })},
+	{"ROLLUP_ALLOWED_LATENESS", durationVar(func(c *Config) *Duration { return &c.Rollup.AllowedLateness })},
+
+	{"BUDGET_PERIOD", durationVar(func(c *Config) *Duration { return &c.Budget.Period })},
+	{"BUDGET_LIMIT", intVar(func(c *Config) *int { return &c.Budget.Limit })},
+	{"BUDGET_PARTICIPANT_LIMITS", intMapVar(func(c *Config) *map[string]int { return &c.Budget.ParticipantLimits })},
+	{"BUDGET_ALERT_THRESHOLDS", intListVar(func(c *Config) *[]int { return &c.Budget.AlertThresholds })},
+	{"BUDGET_WEBHOOK_URL", stringVar(func(c *Config) *string { return &c.Budget.WebhookURL })},
+	{"BUDGET_WEBHOOK_AUTH_HEADER", stringVar(func(c *Config) *string { return &c.Budget.WebhookAuthHeader })},
+	{"BUDGET_WEBHOOK_TIMEOUT", durationVar(func(c *Config) *Duration { return &c.Budget.WebhookTimeout })},
+
+	{"PRICING_RATES_FILE", stringVar(func(c *Config) *string { return &c.Pricing.RatesFile })},
+	{"PRICING_USD_PER_MB", floatVar(func(c *Config) *float64 { return &c.Pricing.USDPerMB })},
+	{"PRICING_USD_PER_CC", floatVar(func(c *Config) *float64 { return &c.Pricing.USDPerCC })},
+
+	{"ANOMALY_METHOD", stringVar(func(c *Config) *string { return &c.Anomaly.Method })},
+	{"ANOMALY_KEY", stringVar(func(c *Config) *string { return &c.Anomaly.Key })},
+	{"ANOMALY_THRESHOLD", floatVar(func(c *Config) *float64 { return &c.Anomaly.Threshold })},
+	{"ANOMALY_WARMUP", intVar(func(c *Config) *int { return &c.Anomaly.Warmup })},
+	{"ANOMALY_EWMA_ALPHA", floatVar(func(c *Config) *float64 { return &c.Anomaly.EWMAAlpha })},
+	{"ANOMALY_MAX_KEYS", intVar(func(c *Config) *int { return &c.Anomaly.MaxKeys })},
+
+	{"API_ADDR", stringVar(func(c *Config) *string { return &c.API.Addr })},
+
+	{"STORE_CAPACITY", intVar(func(c *Config) *int { return &c.Store.Capacity })},
+	{"STORE_TTL", durationVar(func(c *Config) *Duration { return &c.Store.TTL })},
+
+	{"STREAM_BUFFER_SIZE", intVar(func(c *Config) *int { return &c.Stream.BufferSize })},
+
+	{"CORRELATION_ENABLED", boolVar(func(c *Config) *bool { return &c.Correlation.Enabled })},
+	{"CORRELATION_WAIT", durationVar(func(c *Config) *Duration { return &c.Correlation.Wait })},
+	{"CORRELATION_RETENTION", durationVar(func(c *Config) *Duration { return &c.Correlation.Retention })},
+	{"CORRELATION_AUTH_HEADER", stringVar(func(c *Config) *string { return &c.Correlation.AuthHeader })},
+
+	{"DASHBOARD_ENABLED", boolVar(func(c *Config) *bool { return &c.Dashboard.Enabled })},
+	{"DASHBOARD_HISTORY", durationVar(func(c *Config) *Duration { return &c.Dashboard.History })},
+	{"DASHBOARD_REFRESH", durationVar(func(c *Config) *Duration { return &c.Dashboard.Refresh })},
+	{"DASHBOARD_MAX_TRACES", intVar(func(c *Config) *int { return &c.Dashboard.MaxTraces })},
+
+	{"HEALTH_STALE_AFTER", durationVar(func(c *Config) *Duration { return &c.Health.StaleAfter })},
+
+	{"METRICS_SUMMARY_INTERVAL", durationVar(func(c *Config) *Duration { return &c.Metrics.SummaryInterval })},
+}
+
+// applyEnv applies the environment variables which are set and not empty,
+// a value which cannot be parsed is an error rather than being ignored
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, override := range envOverrides {
+		v, ok := lookup(override.name)
+		if !ok || v == "" {
+			continue
+		}
+		if err := override.apply(c, v); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", override.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) firstTarget() *Target {
+	if len(c.Targets) == 0 {
+		c.Targets = append(c.Targets, Target{})
+	}
+	return &c.Targets[0]
+}
+
+func (c *Config) firstExporter() *Exporter {
+	if len(c.Exporters) == 0 {
+		c.Exporters = append(c.Exporters, Exporter{Type: "http"})
+	}
+	return &c.Exporters[0]
+}
+
+func stringVar(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		*field(c) = v
+		return nil
+	}
+}
+
+func boolVar(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", v)
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func intVar(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", v)
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func floatVar(field func(*Config) *float64) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", v)
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func durationVar(field func(*Config) *Duration) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", v)
+		}
+		*field(c) = Duration(parsed)
+		return nil
+	}
+}
+
+// stringListVar parses comma separated strings, e.g. "read,write"
+func stringListVar(field func(*Config) *[]string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		var values []string
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+		*field(c) = values
+		return nil
+	}
+}
+
+// durationListVar parses comma separated durations, e.g. "1m,1h,24h"
+func durationListVar(field func(*Config) *[]Duration) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		var durations []Duration
+		for _, part := range strings.Split(v, ",") {
+			parsed, err := time.ParseDuration(strings.TrimSpace(part))
+			if err != nil {
+				return fmt.Errorf("invalid duration %q", part)
+			}
+			durations = append(durations, Duration(parsed))
+		}
+		*field(c) = durations
+		return nil
+	}
+}
+
+// intListVar parses comma separated integers, e.g. "50,80,100"
+func intListVar(field func(*Config) *[]int) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		var ints []int
+		for _, part := range strings.Split(v, ",") {
+			parsed, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return fmt.Errorf("invalid integer %q", part)
+			}
+			ints = append(ints, parsed)
+		}
+		*field(c) = ints
+		return nil
+	}
+}
+
+// intMapVar parses comma separated key=integer pairs, e.g. "participant=1000000,other=500000"
+func intMapVar(field func(*Config) *map[string]int) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		ints := make(map[string]int)
+		for _, part := range strings.Split(v, ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+			if !ok || key == "" {
+				return fmt.Errorf("invalid pair %q: must be key=value", part)
+			}
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid integer %q for %s", value, key)
+			}
+			ints[key] = parsed
+		}
+		*field(c) = ints
+		return nil
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// ReadSecret returns the secret, or the content of its file without the
+// trailing newline, nil if neither is set. The file is usually a mounted
+// Kubernetes secret, which keeps the secret out of the manifest.
+func ReadSecret(secret string, file string) ([]byte, error) {
+	if file == "" {
+		if secret == "" {
+			return nil, nil
+		}
+		return []byte(secret), nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret file: %w", err)
+	}
+	data = bytes.TrimRight(data, "\r\n")
+	if len(data) == 0 {
+		return nil, fmt.Errorf("secret file %s is empty", file)
+	}
+	return data, nil
+}
@@ -0,0 +1,284 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/DLC-link/cantcost/internal/anomaly"
+	"github.com/DLC-link/cantcost/internal/attribution"
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+// Validate checks the whole configuration and returns all the problems joined together
+func (c *Config) Validate() error {
+	var v validator
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		v.addf("log_level: must be one of debug, info, warn, error, got %q", c.LogLevel)
+	}
+	v.checkPositive("shutdown_grace_period", c.ShutdownGracePeriod)
+
+	if len(c.Targets) == 0 {
+		v.addf("targets: at least one target is required, set TARGET_DEPLOYMENT or the targets list")
+	}
+	targets := make(map[Target]bool)
+	for i, target := range c.Targets {
+		if target.Deployment == "" {
+			v.addf("targets[%d].deployment: must be set", i)
+		}
+		if targets[target] {
+			v.addf("targets[%d]: duplicate target %s", i, target)
+		}
+		targets[target] = true
+	}
+
+	names := make(map[string]bool)
+	for i, exporter := range c.Exporters {
+		if names[exporter.Name] {
+			v.addf("exporters[%d].name: duplicate name %q", i, exporter.Name)
+		}
+		names[exporter.Name] = true
+		switch exporter.Type {
+		case "http":
+			if template, err := exporter.RequestTemplate(); err != nil {
+				v.addf("exporters[%d]: %v", i, err)
+			} else if template != nil {
+				// The rendered URL is checked with a sample line instead
+				if err := template.Validate(exporter.Fields); err != nil {
+					v.addf("exporters[%d]: %v", i, err)
+				}
+			} else {
+				v.checkURL(fmt.Sprintf("exporters[%d].url", i), exporter.URL, true)
+			}
+			if exporter.BatchSize < 1 {
+				v.addf("exporters[%d].batch_size: must be positive, got %d", i, exporter.BatchSize)
+			}
+			v.checkPositive(fmt.Sprintf("exporters[%d].flush_interval", i), exporter.FlushInterval)
+			if exporter.SigningSecret != "" && exporter.SigningSecretFile != "" {
+				v.addf("exporters[%d]: signing_secret and signing_secret_file are exclusive", i)
+			}
+			if _, err := exporters.ParseEncoding(exporter.Encoding); err != nil {
+				v.addf("exporters[%d].encoding: %v", i, err)
+			}
+			if _, err := exporters.ParseContentEncoding(exporter.ContentEncoding); err != nil {
+				v.addf("exporters[%d].content_encoding: %v", i, err)
+			}
+			v.checkOAuth2(fmt.Sprintf("exporters[%d].oauth2", i), exporter.OAuth2)
+			if exporter.OAuth2.Enabled() && exporter.AuthHeader != "" {
+				v.addf("exporters[%d]: auth_header and oauth2 are exclusive", i)
+			}
+			v.checkTLS(fmt.Sprintf("exporters[%d].tls", i), exporter.TLS)
+		case "file":
+			if exporter.Path == "" {
+				v.addf("exporters[%d].path: must be set for the file exporter", i)
+			}
+		default:
+			v.addf("exporters[%d].type: must be http or file, got %q", i, exporter.Type)
+		}
+		v.checkPositive(fmt.Sprintf("exporters[%d].timeout", i), exporter.Timeout)
+		v.checkFilter(fmt.Sprintf("exporters[%d].filter", i), exporter.Filter)
+		if err := exporters.Projection(exporter.Fields).Validate(); err != nil {
+			v.addf("exporters[%d].fields: %v", i, err)
+		}
+	}
+
+	switch c.Validation.Mode {
+	case parser.ValidationWarn, parser.ValidationDrop, parser.ValidationTag:
+	default:
+		v.addf("validation.mode: must be one of warn, drop, tag, got %q", c.Validation.Mode)
+	}
+
+	switch c.DeadLetter.Type {
+	case "":
+	case "file":
+		if c.DeadLetter.File == "" {
+			v.addf("dead_letter.file: must be set for the file dead-letter sink")
+		}
+	case "http":
+		v.checkURL("dead_letter.url", c.DeadLetter.URL, true)
+	default:
+		v.addf("dead_letter.type: must be file or http, got %q", c.DeadLetter.Type)
+	}
+
+	v.checkNotNegative("trace_aggregation.window", c.TraceAggregation.Window)
+
+	if c.Attribution.Policy != "" {
+		if _, err := attribution.ParsePolicy(c.Attribution.Policy); err != nil {
+			v.addf("attribution.policy: %v", err)
+		}
+	}
+	v.checkPositive("attribution.window", c.Attribution.Window)
+	v.checkPositive("attribution.retention", c.Attribution.Retention)
+	v.checkNotNegative("attribution.allowed_lateness", c.Attribution.AllowedLateness)
+
+	for i, window := range c.Rollup.Windows {
+		v.checkPositive(fmt.Sprintf("rollup.windows[%d]", i), window)
+	}
+	v.checkNotNegative("rollup.allowed_lateness", c.Rollup.AllowedLateness)
+
+	v.checkPositive("budget.period", c.Budget.Period)
+	if c.Budget.Limit < 0 {
+		v.addf("budget.limit: must not be negative, got %d", c.Budget.Limit)
+	}
+	for participant, limit := range c.Budget.ParticipantLimits {
+		if limit < 0 {
+			v.addf("budget.participant_limits[%s]: must not be negative, got %d", participant, limit)
+		}
+	}
+	for i, threshold := range c.Budget.AlertThresholds {
+		if threshold <= 0 {
+			v.addf("budget.alert_thresholds[%d]: must be positive, got %d", i, threshold)
+		}
+	}
+	v.checkURL("budget.webhook_url", c.Budget.WebhookURL, false)
+	v.checkPositive("budget.webhook_timeout", c.Budget.WebhookTimeout)
+	v.checkTLS("budget.webhook_tls", c.Budget.WebhookTLS)
+
+	if c.Pricing.USDPerMB < 0 {
+		v.addf("pricing.usd_per_mb: must not be negative, got %g", c.Pricing.USDPerMB)
+	}
+	if c.Pricing.USDPerMB > 0 && c.Pricing.USDPerCC <= 0 {
+		v.addf("pricing.usd_per_cc: must be positive when usd_per_mb is set, got %g", c.Pricing.USDPerCC)
+	}
+
+	if c.Anomaly.Method != "" {
+		if _, err := anomaly.ParseMethod(c.Anomaly.Method); err != nil {
+			v.addf("anomaly.method: %v", err)
+		}
+	}
+	if _, err := anomaly.ParseKey(c.Anomaly.Key); err != nil {
+		v.addf("anomaly.key: %v", err)
+	}
+	if c.Anomaly.Threshold <= 0 {
+		v.addf("anomaly.threshold: must be positive, got %g", c.Anomaly.Threshold)
+	}
+	if c.Anomaly.Warmup < 0 {
+		v.addf("anomaly.warmup: must not be negative, got %d", c.Anomaly.Warmup)
+	}
+	if c.Anomaly.EWMAAlpha <= 0 || c.Anomaly.EWMAAlpha > 1 {
+		v.addf("anomaly.ewma_alpha: must be in (0, 1], got %g", c.Anomaly.EWMAAlpha)
+	}
+	if c.Anomaly.MaxKeys < 1 {
+		v.addf("anomaly.max_keys: must be positive, got %d", c.Anomaly.MaxKeys)
+	}
+
+	if c.API.Addr == "" {
+		v.addf("api.addr: must be set")
+	}
+
+	if c.Store.Capacity < 0 {
+		v.addf("store.capacity: must not be negative, got %d", c.Store.Capacity)
+	}
+	v.checkPositive("store.ttl", c.Store.TTL)
+
+	if c.Stream.BufferSize < 1 {
+		v.addf("stream.buffer_size: must be positive, got %d", c.Stream.BufferSize)
+	}
+
+	v.checkNotNegative("correlation.wait", c.Correlation.Wait)
+	v.checkPositive("correlation.retention", c.Correlation.Retention)
+
+	v.checkPositive("dashboard.history", c.Dashboard.History)
+	v.checkPositive("dashboard.refresh", c.Dashboard.Refresh)
+	if c.Dashboard.MaxTraces < 1 {
+		v.addf("dashboard.max_traces: must be positive, got %d", c.Dashboard.MaxTraces)
+	}
+
+	v.checkNotNegative("health.stale_after", c.Health.StaleAfter)
+
+	v.checkNotNegative("metrics.summary_interval", c.Metrics.SummaryInterval)
+
+	return v.err()
+}
+
+// kinds are the kinds of lines a filter can select
+var kinds = []parser.Kind{
+	parser.KindEventCost,
+	parser.KindTrafficState,
+	parser.KindTrafficReceipt,
+	parser.KindTrafficPurchased,
+	parser.KindTrafficRejection,
+	parser.KindTraceSummary,
+	parser.KindCounterpartyCost,
+	parser.KindRollup,
+	parser.KindAnomaly,
+}
+
+// validator collects the validation problems
+type validator struct {
+	errs []error
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) checkFilter(name string, f Filter) {
+	if f.MinCost < 0 {
+		v.addf("%s.min_cost: must not be negative, got %d", name, f.MinCost)
+	}
+	for i, kind := range f.Kinds {
+		if !slices.Contains(kinds, parser.Kind(kind)) {
+			v.addf("%s.kinds[%d]: unknown kind %q", name, i, kind)
+		}
+	}
+}
+
+func (v *validator) checkOAuth2(name string, o OAuth2) {
+	if !o.Enabled() {
+		return
+	}
+	v.checkURL(name+".token_url", o.TokenURL, true)
+	if o.ClientID == "" {
+		v.addf("%s.client_id: must be set", name)
+	}
+	if (o.ClientSecret == "") == (o.ClientSecretFile == "") {
+		v.addf("%s: exactly one of client_secret and client_secret_file must be set", name)
+	}
+	v.checkNotNegative(name+".refresh_before", o.RefreshBefore)
+}
+
+func (v *validator) checkTLS(name string, t TLS) {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		v.addf("%s: cert_file and key_file must be set together", name)
+	}
+	v.checkPositive(name+".reload_interval", t.ReloadInterval)
+}
+
+func (v *validator) checkPositive(name string, d Duration) {
+	if d <= 0 {
+		v.addf("%s: must be positive, got %s", name, d)
+	}
+}
+
+func (v *validator) checkNotNegative(name string, d Duration) {
+	if d < 0 {
+		v.addf("%s: must not be negative, got %s", name, d)
+	}
+}
+
+// checkURL checks that the URL is an absolute http(s) URL, an empty optional URL is valid
+func (v *validator) checkURL(name string, raw string, required bool) {
+	if raw == "" {
+		if required {
+			v.addf("%s: must be set", name)
+		}
+		return
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.addf("%s: must be an absolute http or https URL, got %q", name, raw)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w:\n%w", ErrInvalidConfig, errors.Join(v.errs...))
+}
@@ -0,0 +1,170 @@
+package correlation
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Correlator)(nil)
+
+// Correlator adds the Ledger API submission reported by the applications to the
+// lines of the same trace, then passes them to the next exporter.
+//
+// The applications usually report a submission after its cost events are
+// logged, so an EventCost line without a known submission is held for up to
+// Wait before being exported as it is. The submissions are kept for Retention,
+// for the later lines of their trace.
+type Correlator struct {
+	Wait      time.Duration `json:"wait"`
+	Retention time.Duration `json:"retention"`
+
+	next        exporters.Exporter
+	submissions map[string]*submission
+	pending     map[string][]*pendingLine
+	mutex       *sync.Mutex
+	windowed    exporters.Windowed
+	now         func() time.Time
+}
+
+type submission struct {
+	parser.Submission
+	receivedAt time.Time
+}
+
+type pendingLine struct {
+	line  *parser.Line
+	since time.Time
+}
+
+func New(wait time.Duration, retention time.Duration, next exporters.Exporter) *Correlator {
+	c := &Correlator{
+		Wait:        wait,
+		Retention:   retention,
+		next:        next,
+		submissions: make(map[string]*submission),
+		pending:     make(map[string][]*pendingLine),
+		mutex:       &sync.Mutex{},
+		now:         time.Now,
+	}
+	// The held lines are exported close to their deadline, and the old
+	// submissions forgotten, by the periodic flush
+	c.windowed = exporters.NewWindowed(tickInterval(wait), c.flush)
+	return c
+}
+
+// Register records a submission and exports the held lines of its trace
+func (c *Correlator) Register(ctx context.Context, s parser.Submission) error {
+	if s.TraceID == "" {
+		return ErrMissingTraceID
+	}
+
+	c.mutex.Lock()
+	c.submissions[s.TraceID] = &submission{Submission: s, receivedAt: c.now()}
+	pending := c.pending[s.TraceID]
+	delete(c.pending, s.TraceID)
+	c.mutex.Unlock()
+
+	for _, p := range pending {
+		p.line.Submission = &s
+		if err := c.next.Export(ctx, p.line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Correlator) Export(ctx context.Context, line *parser.Line) error {
+	c.mutex.Lock()
+	if s, ok := c.submissions[line.TraceID]; ok && line.TraceID != "" {
+		enriched := s.Submission
+		line.Submission = &enriched
+	} else if c.Wait > 0 && line.TraceID != "" && line.Kind == parser.KindEventCost {
+		held := *line
+		c.pending[line.TraceID] = append(c.pending[line.TraceID], &pendingLine{line: &held, since: c.now()})
+		c.mutex.Unlock()
+		return nil
+	}
+	c.mutex.Unlock()
+
+	return c.next.Export(ctx, line)
+}
+
+// QueueDepth returns the number of lines held until their submission comes
+func (c *Correlator) QueueDepth() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	depth := 0
+	for _, lines := range c.pending {
+		depth += len(lines)
+	}
+	return depth
+}
+
+// Start runs the periodic flush in the background until Close
+func (c *Correlator) Start(ctx context.Context) error {
+	if err := c.windowed.Start(ctx); err != nil {
+		return err
+	}
+	return c.next.Start(ctx)
+}
+
+// Flush exports the held lines as they are, then flushes the next exporter
+func (c *Correlator) Flush(ctx context.Context) error {
+	return errors.Join(c.windowed.Flush(ctx), c.next.Flush(ctx))
+}
+
+// Close stops the periodic flush, then closes the next exporter
+func (c *Correlator) Close(ctx context.Context) error {
+	return errors.Join(c.windowed.Close(ctx), c.next.Close(ctx))
+}
+
+func (c *Correlator) flush(ctx context.Context, all bool) {
+	now := c.now()
+	var expired []*parser.Line
+
+	c.mutex.Lock()
+	for traceID, lines := range c.pending {
+		kept := lines[:0]
+		for _, p := range lines {
+			if all || now.Sub(p.since) >= c.Wait {
+				expired = append(expired, p.line)
+			} else {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == 0 {
+			delete(c.pending, traceID)
+		} else {
+			c.pending[traceID] = kept
+		}
+	}
+	for traceID, s := range c.submissions {
+		if now.Sub(s.receivedAt) > c.Retention {
+			delete(c.submissions, traceID)
+		}
+	}
+	c.mutex.Unlock()
+
+	for _, line := range expired {
+		if err := c.next.Export(ctx, line); err != nil {
+			slog.ErrorContext(ctx, "Failed to export uncorrelated line",
+				slog.String("trace_id", line.TraceID),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+// tickInterval checks the held lines often enough to export them close to their deadline
+func tickInterval(wait time.Duration) time.Duration {
+	if wait <= 0 {
+		return time.Minute
+	}
+	return max(wait/4, 100*time.Millisecond)
+}
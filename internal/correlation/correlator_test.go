@@ -0,0 +1,78 @@
+package correlation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters/exporterstest"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func costLine(traceID string) *parser.Line {
+	return &parser.Line{
+		TraceID:     traceID,
+		Kind:        parser.KindEventCost,
+		CostDetails: &parser.EventCostDetails{EventCost: 100},
+	}
+}
+
+func TestCorrelator(t *testing.T) {
+	capture := &exporterstest.Capture{}
+	correlator := New(5*time.Second, time.Minute, capture)
+	now := time.Date(2025, 12, 3, 17, 5, 0, 0, time.UTC)
+	correlator.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	// The cost events are logged before the submission is reported
+	_ = correlator.Export(ctx, costLine("a"))
+	_ = correlator.Export(ctx, costLine("b"))
+	if len(capture.Lines) != 0 {
+		t.Fatalf("exported %d lines, want them held", len(capture.Lines))
+	}
+
+	_ = correlator.Register(ctx, parser.Submission{TraceID: "a", CommandID: "cmd-1", ApplicationID: "wallet"})
+	if len(capture.Lines) != 1 || capture.Lines[0].Submission == nil || capture.Lines[0].Submission.CommandID != "cmd-1" {
+		t.Fatalf("exported %+v, want the line of trace a with its submission", capture.Lines)
+	}
+
+	// A later line of a known trace is enriched right away
+	_ = correlator.Export(ctx, costLine("a"))
+	if len(capture.Lines) != 2 || capture.Lines[1].Submission == nil {
+		t.Fatalf("exported %+v, want the later line of trace a enriched", capture.Lines)
+	}
+
+	// Trace b is never reported, its line is exported as it is after the wait
+	now = now.Add(5 * time.Second)
+	correlator.flush(ctx, false)
+	if len(capture.Lines) != 3 || capture.Lines[2].TraceID != "b" || capture.Lines[2].Submission != nil {
+		t.Fatalf("exported %+v, want the line of trace b without submission", capture.Lines)
+	}
+
+	// The submissions are forgotten after the retention
+	now = now.Add(2 * time.Minute)
+	correlator.flush(ctx, false)
+	_ = correlator.Export(ctx, &parser.Line{TraceID: "a", Kind: parser.KindTrafficState})
+	if last := capture.Lines[len(capture.Lines)-1]; last.Submission != nil {
+		t.Fatalf("line enriched with an expired submission: %+v", last.Submission)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	traceID, err := ParseTraceparent("00-8400687f8dbbef675fb7b6e4661f461d-b7ad6b7169203331-01")
+	if err != nil || traceID != "8400687f8dbbef675fb7b6e4661f461d" {
+		t.Fatalf("ParseTraceparent() = %q, %v", traceID, err)
+	}
+
+	for _, traceparent := range []string{
+		"",
+		"8400687f8dbbef675fb7b6e4661f461d",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-8400687F8DBBEF675FB7B6E4661F461D-b7ad6b7169203331-01",
+		"ff-8400687f8dbbef675fb7b6e4661f461d-b7ad6b7169203331-01",
+	} {
+		if _, err := ParseTraceparent(traceparent); err == nil {
+			t.Errorf("ParseTraceparent(%q) succeeded, want an error", traceparent)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package correlation
+
+import "errors"
+
+var (
+	ErrInvalidTraceparent = errors.New("invalid traceparent")
+	ErrMissingTraceID     = errors.New("missing trace id")
+)
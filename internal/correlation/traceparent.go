@@ -0,0 +1,33 @@
+package correlation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseTraceparent returns the trace id of a W3C traceparent header, e.g.
+// 00-8400687f8dbbef675fb7b6e4661f461d-b7ad6b7169203331-01
+func ParseTraceparent(traceparent string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || !isHex(parts[0], 2) || parts[0] == "ff" || !isHex(parts[1], 32) || !isHex(parts[2], 16) || !isHex(parts[3], 2) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTraceparent, traceparent)
+	}
+	if strings.Trim(parts[1], "0") == "" {
+		return "", fmt.Errorf("%w: all zero trace id", ErrInvalidTraceparent)
+	}
+	return parts[1], nil
+}
+
+// isHex reports whether s is made of n lowercase hexadecimal digits
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
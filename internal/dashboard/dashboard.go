@@ -0,0 +1,278 @@
+package dashboard
+
+import (
+	"container/list"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+	"github.com/DLC-link/cantcost/internal/stream"
+)
+
+var _ exporters.Exporter = (*Dashboard)(nil)
+
+// topSize is the number of traces and counterparties listed on the dashboard
+const topSize = 10
+
+// Dashboard keeps the state shown on the web dashboard over the History period,
+// the live feed is pushed by the stream broker. It must receive the parsed
+// lines and the records of the aggregation stages alike. The state is pruned
+// between Start and Close whether anyone watches or not, and at most MaxTraces
+// traces are kept, the first seen are evicted first.
+type Dashboard struct {
+	History   time.Duration `json:"history"`
+	Refresh   time.Duration `json:"refresh"`
+	MaxTraces int           `json:"max_traces"`
+
+	minutes map[time.Time]*MinuteCost
+	// traces indexes the elements of traceOrder, whose front is the first seen trace
+	traces         map[string]*list.Element
+	traceOrder     *list.List
+	counterparties map[counterpartyKey]parser.CounterpartyCost
+	rollups        map[rollupKey]parser.Rollup
+	broker         *stream.Broker
+	mutex          *sync.Mutex
+	runner         exporters.Runner
+	now            func() time.Time
+}
+
+type counterpartyKey struct {
+	counterparty string
+	windowStart  time.Time
+}
+
+type rollupKey struct {
+	window       string
+	participant  string
+	synchronizer string
+	spanName     string
+}
+
+type MinuteCost struct {
+	Minute time.Time `json:"minute"`
+	Cost   int       `json:"cost"`
+	Events int       `json:"events"`
+}
+
+type TraceCost struct {
+	TraceID       string    `json:"trace_id"`
+	Cost          int       `json:"cost"`
+	Events        int       `json:"events"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+}
+
+type CounterpartyTotal struct {
+	Counterparty   string  `json:"counterparty"`
+	AttributedCost float64 `json:"attributed_cost"`
+	EnvelopeCount  int     `json:"envelope_count"`
+}
+
+// Snapshot is the aggregated state pushed to the dashboard
+type Snapshot struct {
+	Since             time.Time           `json:"since"`
+	CostPerMinute     []MinuteCost        `json:"cost_per_minute"`
+	TopTraces         []TraceCost         `json:"top_traces"`
+	TopCounterparties []CounterpartyTotal `json:"top_counterparties"`
+	Rollups           []parser.Rollup     `json:"rollups"`
+}
+
+func New(history time.Duration, refresh time.Duration, broker *stream.Broker) *Dashboard {
+	return &Dashboard{
+		History:        history,
+		Refresh:        refresh,
+		MaxTraces:      10000,
+		minutes:        make(map[time.Time]*MinuteCost),
+		traces:         make(map[string]*list.Element),
+		traceOrder:     list.New(),
+		counterparties: make(map[counterpartyKey]parser.CounterpartyCost),
+		rollups:        make(map[rollupKey]parser.Rollup),
+		broker:         broker,
+		mutex:          &sync.Mutex{},
+		now:            time.Now,
+	}
+}
+
+// Start prunes the state older than History in the background until Close
+func (d *Dashboard) Start(ctx context.Context) error {
+	d.runner.Start(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(max(d.History/60, time.Second))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.mutex.Lock()
+				d.prune(d.now().Add(-d.History))
+				d.mutex.Unlock()
+			}
+		}
+	})
+	return nil
+}
+
+// Flush does nothing, the dashboard holds no lines for the exporters
+func (d *Dashboard) Flush(ctx context.Context) error {
+	return nil
+}
+
+// Close stops the pruning
+func (d *Dashboard) Close(ctx context.Context) error {
+	return d.runner.Stop(ctx)
+}
+
+func (d *Dashboard) Export(ctx context.Context, line *parser.Line) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	switch line.Kind {
+	case parser.KindEventCost:
+		d.addEventCost(line)
+	case parser.KindCounterpartyCost:
+		cost := *line.CounterpartyCost
+		key := counterpartyKey{counterparty: cost.Counterparty, windowStart: cost.WindowStart}
+		d.counterparties[key] = cost
+	case parser.KindRollup:
+		rollup := *line.Rollup
+		key := rollupKey{
+			window:       rollup.Window,
+			participant:  rollup.Participant,
+			synchronizer: rollup.Synchronizer,
+			spanName:     rollup.SpanName,
+		}
+		if previous, ok := d.rollups[key]; !ok || !rollup.WindowStart.Before(previous.WindowStart) {
+			d.rollups[key] = rollup
+		}
+	}
+	return nil
+}
+
+func (d *Dashboard) addEventCost(line *parser.Line) {
+	timestamp := line.Timestamp
+	if timestamp.IsZero() {
+		timestamp = d.now()
+	}
+	cost := line.CostDetails.EventCost
+
+	minute := timestamp.Truncate(time.Minute)
+	bucket, ok := d.minutes[minute]
+	if !ok {
+		bucket = &MinuteCost{Minute: minute}
+		d.minutes[minute] = bucket
+	}
+	bucket.Cost += cost
+	bucket.Events++
+
+	if line.TraceID == "" {
+		return
+	}
+	var trace *TraceCost
+	if element, ok := d.traces[line.TraceID]; ok {
+		trace = element.Value.(*TraceCost)
+	} else {
+		trace = &TraceCost{TraceID: line.TraceID}
+		d.traces[line.TraceID] = d.traceOrder.PushBack(trace)
+		for d.traceOrder.Len() > d.MaxTraces {
+			d.removeTrace(d.traceOrder.Front())
+		}
+	}
+	trace.Cost += cost
+	trace.Events++
+	if timestamp.After(trace.LastTimestamp) {
+		trace.LastTimestamp = timestamp
+	}
+}
+
+func (d *Dashboard) removeTrace(element *list.Element) {
+	d.traceOrder.Remove(element)
+	delete(d.traces, element.Value.(*TraceCost).TraceID)
+}
+
+// prune drops the state older than since, the mutex must be held
+func (d *Dashboard) prune(since time.Time) {
+	for minute := range d.minutes {
+		if minute.Add(time.Minute).Before(since) {
+			delete(d.minutes, minute)
+		}
+	}
+	for element := d.traceOrder.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*TraceCost).LastTimestamp.Before(since) {
+			d.removeTrace(element)
+		}
+		element = next
+	}
+	for key, cost := range d.counterparties {
+		if cost.WindowEnd.Before(since) {
+			delete(d.counterparties, key)
+		}
+	}
+	for key, rollup := range d.rollups {
+		if rollup.WindowEnd.Before(since) {
+			delete(d.rollups, key)
+		}
+	}
+}
+
+// Snapshot prunes the state older than the History period and returns the rest
+func (d *Dashboard) Snapshot() Snapshot {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	since := d.now().Add(-d.History)
+	d.prune(since)
+	snapshot := Snapshot{
+		Since:             since,
+		CostPerMinute:     make([]MinuteCost, 0, len(d.minutes)),
+		TopTraces:         make([]TraceCost, 0, topSize),
+		TopCounterparties: make([]CounterpartyTotal, 0, topSize),
+		Rollups:           make([]parser.Rollup, 0, len(d.rollups)),
+	}
+
+	for _, bucket := range d.minutes {
+		snapshot.CostPerMinute = append(snapshot.CostPerMinute, *bucket)
+	}
+	sort.Slice(snapshot.CostPerMinute, func(i, j int) bool {
+		return snapshot.CostPerMinute[i].Minute.Before(snapshot.CostPerMinute[j].Minute)
+	})
+
+	traces := make([]TraceCost, 0, len(d.traces))
+	for _, element := range d.traces {
+		traces = append(traces, *element.Value.(*TraceCost))
+	}
+	sort.Slice(traces, func(i, j int) bool {
+		return traces[i].Cost > traces[j].Cost
+	})
+	snapshot.TopTraces = append(snapshot.TopTraces, traces[:min(topSize, len(traces))]...)
+
+	totals := make(map[string]*CounterpartyTotal)
+	for _, cost := range d.counterparties {
+		total, ok := totals[cost.Counterparty]
+		if !ok {
+			total = &CounterpartyTotal{Counterparty: cost.Counterparty}
+			totals[cost.Counterparty] = total
+		}
+		total.AttributedCost += cost.AttributedCost
+		total.EnvelopeCount += cost.EnvelopeCount
+	}
+	counterparties := make([]CounterpartyTotal, 0, len(totals))
+	for _, total := range totals {
+		counterparties = append(counterparties, *total)
+	}
+	sort.Slice(counterparties, func(i, j int) bool {
+		return counterparties[i].AttributedCost > counterparties[j].AttributedCost
+	})
+	snapshot.TopCounterparties = append(snapshot.TopCounterparties, counterparties[:min(topSize, len(counterparties))]...)
+
+	for _, rollup := range d.rollups {
+		snapshot.Rollups = append(snapshot.Rollups, rollup)
+	}
+	sort.Slice(snapshot.Rollups, func(i, j int) bool {
+		return snapshot.Rollups[i].Sum > snapshot.Rollups[j].Sum
+	})
+
+	return snapshot
+}
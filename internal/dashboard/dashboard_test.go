@@ -0,0 +1,88 @@
+package dashboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+	"github.com/DLC-link/cantcost/internal/stream"
+)
+
+func TestSnapshot(t *testing.T) {
+	now := time.Date(2025, 12, 3, 17, 30, 0, 0, time.UTC)
+	dashboard := New(time.Hour, time.Second, stream.New(1))
+	dashboard.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	costLine := func(traceID string, timestamp time.Time, cost int) *parser.Line {
+		return &parser.Line{
+			Timestamp:   timestamp,
+			TraceID:     traceID,
+			Kind:        parser.KindEventCost,
+			CostDetails: &parser.EventCostDetails{EventCost: cost},
+		}
+	}
+	_ = dashboard.Export(ctx, costLine("a", now.Add(-2*time.Hour), 1000))
+	_ = dashboard.Export(ctx, costLine("b", now.Add(-90*time.Second), 100))
+	_ = dashboard.Export(ctx, costLine("c", now.Add(-80*time.Second), 300))
+	_ = dashboard.Export(ctx, costLine("b", now.Add(-10*time.Second), 250))
+
+	window := now.Add(-10 * time.Minute).Truncate(10 * time.Minute)
+	counterparty := func(window time.Time, cost float64) *parser.Line {
+		return &parser.Line{
+			Kind: parser.KindCounterpartyCost,
+			CounterpartyCost: &parser.CounterpartyCost{
+				Counterparty:   "PAR::bob",
+				WindowStart:    window,
+				WindowEnd:      window.Add(10 * time.Minute),
+				AttributedCost: cost,
+				EnvelopeCount:  1,
+			},
+		}
+	}
+	// The windows of a counterparty are added
+	_ = dashboard.Export(ctx, counterparty(window.Add(-10*time.Minute), 40))
+	_ = dashboard.Export(ctx, counterparty(window, 2))
+
+	snapshot := dashboard.Snapshot()
+
+	// Trace a is older than the history
+	if len(snapshot.TopTraces) != 2 || snapshot.TopTraces[0].TraceID != "b" || snapshot.TopTraces[0].Cost != 350 || snapshot.TopTraces[1].TraceID != "c" {
+		t.Fatalf("TopTraces = %+v, want b (350) then c", snapshot.TopTraces)
+	}
+	if len(snapshot.CostPerMinute) != 2 || snapshot.CostPerMinute[0].Cost != 400 || snapshot.CostPerMinute[1].Cost != 250 {
+		t.Fatalf("CostPerMinute = %+v, want 400 then 250", snapshot.CostPerMinute)
+	}
+	if len(snapshot.TopCounterparties) != 1 || snapshot.TopCounterparties[0].AttributedCost != 42 || snapshot.TopCounterparties[0].EnvelopeCount != 2 {
+		t.Fatalf("TopCounterparties = %+v, want PAR::bob with 42", snapshot.TopCounterparties)
+	}
+}
+
+func TestDashboardBoundsTraces(t *testing.T) {
+	now := time.Date(2025, 12, 3, 17, 30, 0, 0, time.UTC)
+	dashboard := New(time.Hour, time.Second, stream.New(1))
+	dashboard.MaxTraces = 2
+	dashboard.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for _, traceID := range []string{"a", "b", "c"} {
+		_ = dashboard.Export(ctx, &parser.Line{
+			Timestamp:   now,
+			TraceID:     traceID,
+			Kind:        parser.KindEventCost,
+			CostDetails: &parser.EventCostDetails{EventCost: 100},
+		})
+	}
+	if _, ok := dashboard.traces["a"]; ok || len(dashboard.traces) != 2 {
+		t.Fatalf("traces = %v, want b and c, the first seen evicted", dashboard.traces)
+	}
+
+	// Without any snapshot, the state older than the history is pruned
+	dashboard.mutex.Lock()
+	dashboard.prune(now.Add(time.Hour + time.Minute))
+	dashboard.mutex.Unlock()
+	if len(dashboard.traces) != 0 || dashboard.traceOrder.Len() != 0 || len(dashboard.minutes) != 0 {
+		t.Errorf("state left after pruning: %d traces, %d minutes", len(dashboard.traces), len(dashboard.minutes))
+	}
+}
@@ -0,0 +1,79 @@
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/stream"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the dashboard page and its event stream, relative to the
+// prefix it is mounted on:
+//
+//	GET /        the page
+//	GET /events  the server-sent events: a "snapshot" every Refresh and a "line" per exported line
+func (d *Dashboard) Handler() http.Handler {
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		// The directory is embedded at build time
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /events", http.HandlerFunc(d.serveEvents))
+	mux.Handle("GET /", http.FileServerFS(assets))
+	return mux
+}
+
+func (d *Dashboard) serveEvents(w http.ResponseWriter, r *http.Request) {
+	subscriber := d.broker.Subscribe(filter.Filter{})
+	defer d.broker.Unsubscribe(subscriber)
+
+	events, err := stream.NewEventWriter(w)
+	if err != nil {
+		return
+	}
+	ticker := time.NewTicker(d.Refresh)
+	defer ticker.Stop()
+
+	if err := d.writeSnapshot(events); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-subscriber.Lines():
+			if !ok {
+				// Disconnected as a slow consumer or on shutdown, the browser
+				// reconnects by itself
+				return
+			}
+			if err := events.Event("line", data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := d.writeSnapshot(events); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (d *Dashboard) writeSnapshot(events *stream.EventWriter) error {
+	data, err := json.Marshal(d.Snapshot())
+	if err != nil {
+		slog.Error("Failed to marshal dashboard snapshot", slog.Any("error", err))
+		return err
+	}
+	return events.Event("snapshot", data)
+}
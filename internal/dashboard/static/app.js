@@ -0,0 +1,155 @@
+"use strict";
+
+// feedSize is the number of lines kept in the live feed
+const feedSize = 50;
+
+const status = document.getElementById("status");
+const feed = document.getElementById("feed");
+
+function cell(row, text, className) {
+  const td = document.createElement("td");
+  td.textContent = text;
+  if (className) {
+    td.className = className;
+  }
+  row.appendChild(td);
+}
+
+function formatNumber(value) {
+  return Math.round(value).toLocaleString();
+}
+
+function formatTime(value) {
+  const date = new Date(value);
+  // The derived records have no log timestamp
+  return value && date.getFullYear() > 1 ? date.toLocaleTimeString() : "";
+}
+
+function replaceRows(id, items, columns) {
+  const body = document.getElementById(id);
+  body.replaceChildren();
+  for (const item of items) {
+    const row = document.createElement("tr");
+    for (const [value, className] of columns(item)) {
+      cell(row, value, className);
+    }
+    body.appendChild(row);
+  }
+}
+
+function details(line) {
+  switch (line.kind) {
+    case "event_cost":
+      return `cost ${formatNumber(line.cost_details.event_cost)}, ${line.cost_details.envelopes_cost.length} envelopes`;
+    case "traffic_rejection":
+      return `${line.traffic_rejection.code}: required ${line.traffic_rejection.required}, available ${line.traffic_rejection.available}`;
+    case "trace_summary":
+      return `total cost ${formatNumber(line.trace_summary.total_cost)}, ${line.trace_summary.event_count} events`;
+    case "counterparty_cost":
+      return `${line.counterparty_cost.counterparty}: ${formatNumber(line.counterparty_cost.attributed_cost)}`;
+    case "rollup":
+      return `${line.rollup.window} ${line.rollup.span_name}: sum ${formatNumber(line.rollup.sum)}`;
+    case "anomaly":
+      return `${line.anomaly.key} ${line.anomaly.metric} ${line.anomaly.value} (score ${line.anomaly.score.toFixed(1)})`;
+    default:
+      return "";
+  }
+}
+
+function addLine(line) {
+  const row = document.createElement("tr");
+  cell(row, formatTime(line["@timestamp"]));
+  cell(row, line.kind || "", "kind-" + line.kind);
+  cell(row, line.trace_id || "", "id");
+  cell(row, line.participant || "");
+  cell(row, details(line));
+  feed.prepend(row);
+  while (feed.children.length > feedSize) {
+    feed.lastChild.remove();
+  }
+}
+
+function drawChart(minutes) {
+  const canvas = document.getElementById("chart");
+  const ratio = window.devicePixelRatio || 1;
+  const width = canvas.clientWidth;
+  const height = canvas.clientHeight;
+  canvas.width = width * ratio;
+  canvas.height = height * ratio;
+
+  const ctx = canvas.getContext("2d");
+  ctx.scale(ratio, ratio);
+  ctx.clearRect(0, 0, width, height);
+  ctx.font = "11px system-ui, sans-serif";
+  ctx.fillStyle = "#52606d";
+
+  if (minutes.length === 0) {
+    ctx.fillText("No cost events yet", 8, height / 2);
+    return;
+  }
+
+  const padding = { left: 64, right: 8, top: 8, bottom: 20 };
+  const plotWidth = width - padding.left - padding.right;
+  const plotHeight = height - padding.top - padding.bottom;
+  const maxCost = Math.max(...minutes.map((m) => m.cost), 1);
+  const barWidth = plotWidth / minutes.length;
+
+  ctx.fillText(formatNumber(maxCost), 4, padding.top + 10);
+  ctx.fillText("0", 4, padding.top + plotHeight);
+  ctx.fillText(formatTime(minutes[0].minute), padding.left, height - 4);
+  const last = formatTime(minutes[minutes.length - 1].minute);
+  ctx.fillText(last, width - padding.right - ctx.measureText(last).width, height - 4);
+
+  ctx.fillStyle = "#3e7bfa";
+  minutes.forEach((m, i) => {
+    const barHeight = (m.cost / maxCost) * plotHeight;
+    ctx.fillRect(
+      padding.left + i * barWidth + 1,
+      padding.top + plotHeight - barHeight,
+      Math.max(barWidth - 2, 1),
+      barHeight,
+    );
+  });
+}
+
+function applySnapshot(snapshot) {
+  drawChart(snapshot.cost_per_minute);
+  replaceRows("traces", snapshot.top_traces, (t) => [
+    [t.trace_id, "id"],
+    [formatNumber(t.events), "number"],
+    [formatNumber(t.cost), "number"],
+  ]);
+  replaceRows("counterparties", snapshot.top_counterparties, (c) => [
+    [c.counterparty, "id"],
+    [formatNumber(c.envelope_count), "number"],
+    [formatNumber(c.attributed_cost), "number"],
+  ]);
+  replaceRows("rollups", snapshot.rollups, (r) => [
+    [r.window],
+    [formatTime(r.window_start)],
+    [r.participant, "id"],
+    [r.span_name],
+    [formatNumber(r.count), "number"],
+    [formatNumber(r.sum), "number"],
+    [formatNumber(r.p50), "number"],
+    [formatNumber(r.p95), "number"],
+    [formatNumber(r.p99), "number"],
+  ]);
+}
+
+function connect() {
+  const source = new EventSource("events");
+  source.onopen = () => {
+    status.textContent = "connected";
+    status.className = "status connected";
+  };
+  source.onerror = () => {
+    // EventSource reconnects by itself
+    status.textContent = "disconnected";
+    status.className = "status disconnected";
+  };
+  source.addEventListener("snapshot", (event) => applySnapshot(JSON.parse(event.data)));
+  source.addEventListener("line", (event) => addLine(JSON.parse(event.data)));
+}
+
+connect();
@@ -0,0 +1,96 @@
+package deadletter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	sink := NewFileSink(path)
+	ctx := context.Background()
+	written := []*Record{
+		NewRecord("first line", errors.New("no separator")),
+		NewRecord("second line", errors.New("invalid payload")),
+	}
+	for _, record := range written {
+		if err := sink.Write(ctx, record); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var read []*Record
+	if err := Read(file, func(record *Record) error {
+		read = append(read, record)
+		return nil
+	}); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+
+	if len(read) != len(written) {
+		t.Fatalf("Read() returned %d records, want %d", len(read), len(written))
+	}
+	for i := range written {
+		if !read[i].Timestamp.Equal(written[i].Timestamp) || read[i].Line != written[i].Line || read[i].Error != written[i].Error || read[i].Version != written[i].Version {
+			t.Errorf("record %d = %+v, want %+v", i, read[i], written[i])
+		}
+	}
+}
+
+func TestReadSkipsEmptyLinesAndStops(t *testing.T) {
+	input := "\n" + `{"line":"a"}` + "\n\n" + `{"line":"b"}` + "\n"
+	errStop := errors.New("stop")
+	var lines []string
+	err := Read(bytes.NewBufferString(input), func(record *Record) error {
+		lines = append(lines, record.Line)
+		return errStop
+	})
+	if !errors.Is(err, errStop) || len(lines) != 1 || lines[0] != "a" {
+		t.Errorf("Read() = %v, %v, want the error of fn after the first record", lines, err)
+	}
+
+	if err := Read(bytes.NewBufferString("not json\n"), func(*Record) error { return nil }); err == nil {
+		t.Error("Read() accepted an invalid record")
+	}
+}
+
+func TestHTTPRoundTrip(t *testing.T) {
+	var received Record
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("invalid record: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, "Bearer secret")
+	record := NewRecord("a line", errors.New("invalid payload"))
+	if err := sink.Write(context.Background(), record); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	if received.Line != record.Line || received.Error != record.Error {
+		t.Errorf("received %+v, want %+v", received, record)
+	}
+
+	status = http.StatusInternalServerError
+	if err := sink.Write(context.Background(), record); err == nil {
+		t.Error("Write() succeeded with a 500 response")
+	}
+}
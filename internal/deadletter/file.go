@@ -0,0 +1,44 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+var _ Sink = (*File)(nil)
+
+// File appends the records as JSON lines to a local file
+type File struct {
+	Path string `json:"path"`
+
+	mutex *sync.Mutex
+}
+
+func NewFileSink(path string) *File {
+	return &File{
+		Path:  path,
+		mutex: &sync.Mutex{},
+	}
+}
+
+func (f *File) Write(ctx context.Context, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(data)
+	return err
+}
@@ -0,0 +1,55 @@
+package deadletter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+var _ Sink = (*HTTP)(nil)
+
+// HTTP posts the records as JSON to a separate endpoint
+type HTTP struct {
+	URL                 string `json:"url"`
+	AuthorizationHeader string `json:"authorization_header"`
+}
+
+func NewHTTPSink(url string, authHeader string) *HTTP {
+	return &HTTP{
+		URL:                 url,
+		AuthorizationHeader: authHeader,
+	}
+}
+
+func (h *HTTP) Write(ctx context.Context, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	if h.AuthorizationHeader != "" {
+		req.Header.Add("Authorization", h.AuthorizationHeader)
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("failed to write dead-letter record, status code: " + resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package deadletter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/version"
+)
+
+// Record is a quarantined log line together with the reason why it could not be processed
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	Error     string    `json:"error"`
+	Line      string    `json:"line"`
+	Version   string    `json:"version"`
+}
+
+type Sink interface {
+	Write(ctx context.Context, record *Record) error
+}
+
+func NewRecord(line string, err error) *Record {
+	return &Record{
+		Timestamp: time.Now().UTC(),
+		Error:     err.Error(),
+		Line:      line,
+		Version:   version.Version,
+	}
+}
+
+// Read calls fn for every record of a dead-letter file written by the File sink
+func Read(r io.Reader, fn func(*Record) error) error {
+	scanner := bufio.NewScanner(r)
+	// Canton log lines with long recipient lists easily exceed the default token size
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return err
+		}
+		if err := fn(&record); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
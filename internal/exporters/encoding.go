@@ -0,0 +1,129 @@
+package exporters
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Encoding is the format of the body of the HTTP requests
+type Encoding string
+
+const (
+	// EncodingJSON sends an HTTPRequest as JSON
+	EncodingJSON Encoding = "json"
+	// EncodingJSONLines sends the lines as JSON lines, without the HTTPRequest envelope
+	EncodingJSONLines Encoding = "jsonl"
+	// EncodingCBOR sends an HTTPRequest as CBOR, with the JSON field names
+	EncodingCBOR Encoding = "cbor"
+	// EncodingProtobuf sends a cantcost.v1.ExportRequest, see proto/cantcost/v1/export.proto
+	EncodingProtobuf Encoding = "protobuf"
+)
+
+func ParseEncoding(s string) (Encoding, error) {
+	switch e := Encoding(s); e {
+	case "":
+		return EncodingJSON, nil
+	case EncodingJSON, EncodingJSONLines, EncodingCBOR, EncodingProtobuf:
+		return e, nil
+	}
+	return "", fmt.Errorf("%w: %q, must be json, jsonl, cbor or protobuf", ErrUnknownEncoding, s)
+}
+
+func (e Encoding) ContentType() string {
+	switch e {
+	case EncodingJSONLines:
+		return "application/x-ndjson"
+	case EncodingCBOR:
+		return "application/cbor"
+	case EncodingProtobuf:
+		return "application/x-protobuf"
+	}
+	return "application/json"
+}
+
+// Text tells whether the body is readable in the logs
+func (e Encoding) Text() bool {
+	return e == EncodingJSON || e == EncodingJSONLines
+}
+
+// cborMode keeps the precision of the timestamps, which are integer seconds by default
+var cborMode = sync.OnceValue(func() cbor.EncMode {
+	mode, err := cbor.EncOptions{Time: cbor.TimeRFC3339Nano}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+})
+
+// Marshal encodes the lines, which are MessageLine or their projection
+func (e Encoding) Marshal(lines []any) ([]byte, error) {
+	switch e {
+	case EncodingJSONLines:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		for _, line := range lines {
+			if err := encoder.Encode(line); err != nil {
+				return nil, err
+			}
+		}
+		return buf.Bytes(), nil
+	case EncodingCBOR:
+		return cborMode().Marshal(HTTPRequest{Count: len(lines), Lines: lines})
+	case EncodingProtobuf:
+		return marshalProtobuf(lines)
+	}
+	return json.Marshal(HTTPRequest{Count: len(lines), Lines: lines})
+}
+
+// ContentEncoding is the compression of the body of the HTTP requests, empty
+// when the body is not compressed
+type ContentEncoding string
+
+const (
+	ContentEncodingGzip ContentEncoding = "gzip"
+	ContentEncodingZstd ContentEncoding = "zstd"
+)
+
+func ParseContentEncoding(s string) (ContentEncoding, error) {
+	switch c := ContentEncoding(s); c {
+	case "", "identity":
+		return "", nil
+	case ContentEncodingGzip, ContentEncodingZstd:
+		return c, nil
+	}
+	return "", fmt.Errorf("%w: %q, must be gzip or zstd", ErrUnknownEncoding, s)
+}
+
+// zstdEncoder is shared by the exporters, EncodeAll is safe for concurrent use
+var zstdEncoder = sync.OnceValue(func() *zstd.Encoder {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		panic(err)
+	}
+	return encoder
+})
+
+// Compress returns the compressed data
+func (c ContentEncoding) Compress(data []byte) ([]byte, error) {
+	switch c {
+	case ContentEncodingGzip:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case ContentEncodingZstd:
+		return zstdEncoder().EncodeAll(data, nil), nil
+	}
+	return data, nil
+}
@@ -0,0 +1,94 @@
+package exporters
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cantcostv1 "github.com/DLC-link/cantcost/proto/cantcost/v1"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEncodings(t *testing.T) {
+	var body []byte
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		var reader io.Reader = r.Body
+		switch r.Header.Get("Content-Encoding") {
+		case "gzip":
+			reader, _ = gzip.NewReader(r.Body)
+		case "zstd":
+			decoder, _ := zstd.NewReader(r.Body)
+			defer decoder.Close()
+			reader = decoder
+		}
+		body, _ = io.ReadAll(reader)
+	}))
+	defer server.Close()
+
+	export := func(encoding Encoding, contentEncoding ContentEncoding, fields Projection) {
+		exporter := NewHTTPExporter(server.URL, "", 2, 0, fields)
+		exporter.Encoding = encoding
+		exporter.ContentEncoding = contentEncoding
+		ctx := context.Background()
+		for _, cost := range []int{100, 200} {
+			if err := exporter.Export(ctx, costLine(cost)); err != nil {
+				t.Fatalf("Export(%s, %s) error: %v", encoding, contentEncoding, err)
+			}
+		}
+	}
+
+	export(EncodingJSONLines, ContentEncodingGzip, Projection{"cost_details.event_cost"})
+	if want := "{\"cost_details\":{\"event_cost\":100}}\n{\"cost_details\":{\"event_cost\":200}}\n"; string(body) != want {
+		t.Errorf("jsonl body = %q, want %q", body, want)
+	}
+	if got := headers.Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("jsonl Content-Type = %q", got)
+	}
+
+	export(EncodingCBOR, ContentEncodingZstd, nil)
+	var request struct {
+		Count int              `cbor:"count"`
+		Lines []map[string]any `cbor:"lines"`
+	}
+	if err := cbor.Unmarshal(body, &request); err != nil {
+		t.Fatalf("invalid CBOR body: %v", err)
+	}
+	if request.Count != 2 || request.Lines[1]["trace_id"] != "8400687f8dbbef675fb7b6e4661f461d" {
+		t.Errorf("CBOR request = %+v", request)
+	}
+
+	export(EncodingProtobuf, "", nil)
+	var decoded cantcostv1.ExportRequest
+	if err := proto.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("invalid protobuf body: %v", err)
+	}
+	if decoded.GetCount() != 2 || len(decoded.GetLines()) != 2 {
+		t.Fatalf("protobuf count = %d, lines = %d, want 2", decoded.GetCount(), len(decoded.GetLines()))
+	}
+	line := decoded.GetLines()[1]
+	if line.GetTraceId() != "8400687f8dbbef675fb7b6e4661f461d" {
+		t.Errorf("protobuf trace_id = %q", line.GetTraceId())
+	}
+	if cost := line.GetCostDetails().GetEventCost(); cost != 200 {
+		t.Errorf("protobuf cost_details.event_cost = %d, want 200", cost)
+	}
+	if line.GetKind() != "event_cost" {
+		t.Errorf("protobuf kind = %q, want event_cost", line.GetKind())
+	}
+
+	export(EncodingProtobuf, "", Projection{"trace_id"})
+	if err := proto.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("invalid protobuf body: %v", err)
+	}
+	if projected := decoded.GetLines()[0].GetProjectedJson(); !bytes.Equal(projected, []byte(`{"trace_id":"8400687f8dbbef675fb7b6e4661f461d"}`)) {
+		t.Errorf("protobuf projected_json = %s", projected)
+	}
+}
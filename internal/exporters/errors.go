@@ -0,0 +1,10 @@
+package exporters
+
+import "errors"
+
+var (
+	ErrUnknownField    = errors.New("unknown message line field")
+	ErrUnknownEncoding = errors.New("unknown encoding")
+	ErrInvalidTemplate = errors.New("invalid template")
+	ErrNotStarted      = errors.New("exporter not started")
+)
@@ -0,0 +1,28 @@
+// Package exporterstest provides the exporters shared by the tests of the stages.
+package exporterstest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Capture)(nil)
+
+// Capture records the exported lines, so that a test can check what a stage
+// passed on to its next exporter
+type Capture struct {
+	exporters.NopLifecycle
+
+	Lines []*parser.Line
+	mutex sync.Mutex
+}
+
+func (c *Capture) Export(ctx context.Context, line *parser.Line) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.Lines = append(c.Lines, line)
+	return nil
+}
@@ -0,0 +1,130 @@
+package exporters
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ Exporter = (*File)(nil)
+
+// File appends the lines as JSON lines to a local file. The file is open
+// between Start and Close, the lines are buffered and written every
+// FlushInterval and on Flush.
+type File struct {
+	Path          string        `json:"path"`
+	Fields        Projection    `json:"fields,omitempty"`
+	FlushInterval time.Duration `json:"flush_interval,omitempty"`
+
+	file   *os.File
+	writer *bufio.Writer
+	mutex  *sync.Mutex
+	runner Runner
+}
+
+func NewFileExporter(path string, fields Projection) *File {
+	return &File{
+		Path:          path,
+		Fields:        fields,
+		FlushInterval: time.Second,
+		mutex:         &sync.Mutex{},
+	}
+}
+
+// Check checks that the directory of the file exists
+func (f *File) Check(ctx context.Context) error {
+	dir := filepath.Dir(f.Path)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
+
+// Start opens the file, in append mode, and writes the buffered lines every
+// FlushInterval, if it is set
+func (f *File) Start(ctx context.Context) error {
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	f.file = file
+	f.writer = bufio.NewWriter(file)
+	f.mutex.Unlock()
+
+	if f.FlushInterval <= 0 {
+		return nil
+	}
+	f.runner.Start(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(f.FlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := f.Flush(ctx); err != nil {
+					slog.ErrorContext(ctx, "Failed to flush file exporter", slog.String("path", f.Path), slog.Any("error", err))
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// Flush writes the buffered lines to the file
+func (f *File) Flush(ctx context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.writer == nil {
+		return nil
+	}
+	return f.writer.Flush()
+}
+
+// Close stops the periodic flush, writes the buffered lines and closes the file
+func (f *File) Close(ctx context.Context) error {
+	err := f.runner.Stop(ctx)
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.file == nil {
+		return err
+	}
+	err = errors.Join(err, f.writer.Flush(), f.file.Close())
+	f.file, f.writer = nil, nil
+	return err
+}
+
+func (f *File) Export(ctx context.Context, line *parser.Line) error {
+	projected, err := f.Fields.Apply(line)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(projected)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.writer == nil {
+		return fmt.Errorf("%w: %s", ErrNotStarted, f.Path)
+	}
+	_, err = f.writer.Write(data)
+	return err
+}
@@ -0,0 +1,48 @@
+package exporters
+
+import (
+	"context"
+
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ Exporter = (*Filtered)(nil)
+
+// Filtered passes only the lines matching its filter to the next exporter
+type Filtered struct {
+	Filter filter.Filter `json:"filter"`
+
+	next Exporter
+}
+
+func NewFiltered(f filter.Filter, next Exporter) *Filtered {
+	return &Filtered{
+		Filter: f,
+		next:   next,
+	}
+}
+
+func (f *Filtered) Export(ctx context.Context, line *parser.Line) error {
+	if !f.Filter.Match(line) {
+		return nil
+	}
+	return f.next.Export(ctx, line)
+}
+
+// Unwrap returns the next exporter, so that its optional interfaces are found
+func (f *Filtered) Unwrap() Exporter {
+	return f.next
+}
+
+func (f *Filtered) Start(ctx context.Context) error {
+	return f.next.Start(ctx)
+}
+
+func (f *Filtered) Flush(ctx context.Context) error {
+	return f.next.Flush(ctx)
+}
+
+func (f *Filtered) Close(ctx context.Context) error {
+	return f.next.Close(ctx)
+}
@@ -3,32 +3,55 @@ package exporters
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/DLC-link/cantcost/internal/parser"
+	"github.com/DLC-link/cantcost/pkg/signing"
 )
 
 var _ Exporter = (*HTTP)(nil)
 
+// HTTP posts the lines in batches of BatchSize. A partial batch is posted every
+// FlushInterval and on Flush, so that a quiet pod does not hold lines forever.
 type HTTP struct {
-	URL                 string `json:"url"`
-	AuthorizationHeader string `json:"authorization_header"`
-	BatchSize           int    `json:"batch_size,omitempty"`
+	URL                 string          `json:"url"`
+	AuthorizationHeader string          `json:"authorization_header"`
+	BatchSize           int             `json:"batch_size,omitempty"`
+	FlushInterval       time.Duration   `json:"flush_interval,omitempty"`
+	Fields              Projection      `json:"fields,omitempty"`
+	Encoding            Encoding        `json:"encoding"`
+	ContentEncoding     ContentEncoding `json:"content_encoding,omitempty"`
+	// Signer signs the requests, if it is set
+	Signer *signing.Signer `json:"-"`
+	// Client sends the requests, e.g. with mutual TLS or OAuth2
+	Client *http.Client `json:"-"`
+	// Template renders the method, the URL, the headers and the body of the
+	// requests, if it is set
+	Template *RequestTemplate `json:"-"`
 
-	batch []*parser.Line
-	mutex *sync.Mutex
+	// batch holds the projected lines which are not posted yet
+	batch  []pending
+	mutex  *sync.Mutex
+	runner Runner
+}
+
+// pending is a projected line of the batch, with the request it is posted with
+type pending struct {
+	request request
+	line    any
 }
 
 type HTTPRequest struct {
-	Count int                   `json:"count"`
-	Lines []*parser.MessageLine `json:"lines"`
+	Count int `json:"count"`
+	// Lines are the MessageLine of the exported lines, or their projection
+	Lines []any `json:"lines"`
 }
 
-func NewHTTPExporter(url string, authHeader string, batchSize int) *HTTP {
+func NewHTTPExporter(url string, authHeader string, batchSize int, flushInterval time.Duration, fields Projection) *HTTP {
 	if batchSize <= 0 {
 		batchSize = 10
 	}
@@ -36,30 +59,186 @@ func NewHTTPExporter(url string, authHeader string, batchSize int) *HTTP {
 		URL:                 url,
 		AuthorizationHeader: authHeader,
 		BatchSize:           batchSize,
-		batch:               make([]*parser.Line, 0),
+		FlushInterval:       flushInterval,
+		Fields:              fields,
+		Encoding:            EncodingJSON,
+		Client:              http.DefaultClient,
+		batch:               make([]pending, 0, batchSize),
 		mutex:               &sync.Mutex{},
 	}
 }
 
+// Start posts the partial batch every FlushInterval, if it is set
+func (h *HTTP) Start(ctx context.Context) error {
+	if h.FlushInterval <= 0 {
+		return nil
+	}
+	h.runner.Start(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(h.FlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := h.Flush(ctx); err != nil {
+					slog.ErrorContext(ctx, "Failed to flush HTTP exporter batch", slog.Any("error", err))
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// Check sends a HEAD request to the URL, any response means that the endpoint is
+// reachable, even an error status since the endpoint may only accept POST. A
+// templated URL is rendered with a sample line.
+func (h *HTTP) Check(ctx context.Context) error {
+	target := h.URL
+	if h.Template != nil {
+		line := sampleLine()
+		sample, err := h.Template.request(line.ToMessageLine())
+		if err != nil {
+			return err
+		}
+		target = sample.url
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Export adds the line to the batch and posts the batch once it is full
 func (h *HTTP) Export(ctx context.Context, line *parser.Line) error {
-	var request = HTTPRequest{
-		Count: 1,
-		Lines: []*parser.MessageLine{line.ToMessageLine()},
+	projected, err := h.Fields.Apply(line)
+	if err != nil {
+		return err
+	}
+	var req request
+	if h.Template != nil {
+		// The request is rendered with the whole line, the projection only
+		// applies to the body
+		if req, err = h.Template.request(line.ToMessageLine()); err != nil {
+			return err
+		}
 	}
 
-	data, err := json.Marshal(request)
+	h.mutex.Lock()
+	h.batch = append(h.batch, pending{request: req, line: projected})
+	var full []pending
+	if len(h.batch) >= h.BatchSize {
+		full = h.take()
+	}
+	h.mutex.Unlock()
+
+	if full == nil {
+		return nil
+	}
+	return h.post(ctx, full)
+}
+
+// Flush posts the partial batch. A batch which fails is dropped, the error is returned.
+func (h *HTTP) Flush(ctx context.Context) error {
+	h.mutex.Lock()
+	lines := h.take()
+	h.mutex.Unlock()
+
+	if len(lines) == 0 {
+		return nil
+	}
+	return h.post(ctx, lines)
+}
+
+// Close stops the periodic flush and posts the partial batch
+func (h *HTTP) Close(ctx context.Context) error {
+	return errors.Join(h.runner.Stop(ctx), h.Flush(ctx))
+}
+
+// QueueDepth returns the number of lines of the partial batch
+func (h *HTTP) QueueDepth() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return len(h.batch)
+}
+
+// take returns the batch and starts a new one, the mutex must be held
+func (h *HTTP) take() []pending {
+	lines := h.batch
+	h.batch = make([]pending, 0, h.BatchSize)
+	return lines
+}
+
+// post sends one request per rendered request of the batch, in the order of
+// their first line. Without a template the whole batch is a single request.
+func (h *HTTP) post(ctx context.Context, batch []pending) error {
+	var requests []request
+	groups := make(map[string][]any)
+	for _, p := range batch {
+		key := p.request.key()
+		if _, ok := groups[key]; !ok {
+			requests = append(requests, p.request)
+		}
+		groups[key] = append(groups[key], p.line)
+	}
+
+	var errs []error
+	for _, r := range requests {
+		if err := h.send(ctx, r, groups[r.key()]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *HTTP) send(ctx context.Context, r request, lines []any) error {
+	method, target, text := http.MethodPost, h.URL, h.Encoding.Text()
+	if r.method != "" {
+		method, target = r.method, r.url
+	}
+
+	var data []byte
+	var err error
+	if h.Template != nil && h.Template.body != nil {
+		data, err = h.Template.render(lines)
+		text = true
+	} else {
+		data, err = h.Encoding.Marshal(lines)
+	}
+	if err != nil {
+		return err
+	}
+	body, err := h.ContentEncoding.Compress(data)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
 	req.Header.Add("Authorization", h.AuthorizationHeader)
-	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Content-Type", h.Encoding.ContentType())
+	if h.ContentEncoding != "" {
+		req.Header.Add("Content-Encoding", string(h.ContentEncoding))
+	}
+	// The custom headers replace the default ones, e.g. Content-Type
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if h.Signer != nil {
+		// The signature covers the body as it is sent, compressed
+		if err := h.Signer.Sign(req, body); err != nil {
+			return err
+		}
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := h.Client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -69,10 +248,10 @@ func (h *HTTP) Export(ctx context.Context, line *parser.Line) error {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		if resp.StatusCode == http.StatusUnprocessableEntity {
+		if resp.StatusCode == http.StatusUnprocessableEntity && text {
 			slog.ErrorContext(ctx, "HTTP exporter received 422 Unprocessable Entity. Check if the log line format matches the expected schema.", slog.String("data", string(data)))
 		}
-		return errors.New("failed to export log line, status code: " + resp.Status)
+		return errors.New("failed to export log lines, status code: " + resp.Status)
 	}
 
 	return nil
@@ -0,0 +1,43 @@
+package exporters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestHTTPBatching(t *testing.T) {
+	var mutex sync.Mutex
+	var counts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request HTTPRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		mutex.Lock()
+		counts = append(counts, request.Count)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	exporter := NewHTTPExporter(server.URL, "", 2, 0, nil)
+	ctx := context.Background()
+	for _, cost := range []int{100, 200, 300} {
+		if err := exporter.Export(ctx, costLine(cost)); err != nil {
+			t.Fatalf("Export() error: %v", err)
+		}
+	}
+	if len(counts) != 1 || counts[0] != 2 {
+		t.Fatalf("batches before Flush = %v, want [2]", counts)
+	}
+
+	if err := exporter.Close(ctx); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if len(counts) != 2 || counts[1] != 1 {
+		t.Errorf("batches after Close = %v, want [2 1]", counts)
+	}
+}
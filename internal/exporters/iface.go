@@ -2,33 +2,245 @@ package exporters
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/DLC-link/cantcost/internal/parser"
 )
 
 type Exporter interface {
+	// Start starts the background work of the exporter, if it has any
+	Start(ctx context.Context) error
 	Export(ctx context.Context, line *parser.Line) error
+	// Flush exports what the exporter holds, e.g. a batch or the open windows
+	Flush(ctx context.Context) error
+	// Close stops the background work and releases the resources, after a Flush
+	Close(ctx context.Context) error
 }
 
+// Checker is implemented by the exporters which can tell whether their
+// destination is reachable, without exporting anything
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// Queue is implemented by the exporters which hold lines before exporting them
+type Queue interface {
+	// QueueDepth returns the number of lines held
+	QueueDepth() int
+}
+
+// ExportObserver is told about every export of the members of Exporters
+type ExportObserver interface {
+	ObserveExport(exporter string, duration time.Duration, err error)
+}
+
+// unwrapper is implemented by the exporters which wrap another one, e.g. Filtered
+type unwrapper interface {
+	Unwrap() Exporter
+}
+
+// as finds the first exporter of the wrapping chain which is a T
+func as[T any](e Exporter) (T, bool) {
+	for {
+		if t, ok := e.(T); ok {
+			return t, true
+		}
+		w, ok := e.(unwrapper)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		e = w.Unwrap()
+	}
+}
+
+// NopLifecycle implements the lifecycle methods of an exporter which has no
+// background work and holds nothing, to be embedded
+type NopLifecycle struct{}
+
+func (NopLifecycle) Start(ctx context.Context) error { return nil }
+func (NopLifecycle) Flush(ctx context.Context) error { return nil }
+func (NopLifecycle) Close(ctx context.Context) error { return nil }
+
+// Exporters fans the lines out to every exporter concurrently. A failing or slow
+// exporter does not prevent the others from receiving the line: the errors are
+// joined together, and each exporter can have its own timeout.
 type Exporters struct {
-	exporters []Exporter
+	exporters []*member
+	observer  ExportObserver
+}
+
+// member is an exporter of the fan-out with its counters
+type member struct {
+	name     string
+	exporter Exporter
+	timeout  time.Duration
+	observer ExportObserver
+
+	succeeded atomic.Int64
+	failed    atomic.Int64
+}
+
+// Stats are the export counters of an exporter
+type Stats struct {
+	Name      string `json:"name"`
+	Succeeded int64  `json:"succeeded"`
+	Failed    int64  `json:"failed"`
 }
 
 func New(exporters ...Exporter) *Exporters {
-	return &Exporters{
-		exporters: exporters,
+	e := &Exporters{}
+	for _, exporter := range exporters {
+		e.AddExporter(exporter)
 	}
+	return e
 }
 
+// Export passes the line to every exporter and waits for all of them. Each
+// exporter gets its own shallow copy of the line: an exporter may set the
+// fields of the line, as the enrichment stages set CostEstimate and Submission,
+// but the records they point to are shared and must not be modified.
 func (e *Exporters) Export(ctx context.Context, line *parser.Line) error {
-	for _, exporter := range e.exporters {
-		if err := exporter.Export(ctx, line); err != nil {
-			return err
+	if len(e.exporters) == 1 {
+		return e.exporters[0].export(ctx, line)
+	}
+
+	errs := make([]error, len(e.exporters))
+	var wg sync.WaitGroup
+	for i, m := range e.exporters {
+		copied := *line
+		wg.Go(func() {
+			errs[i] = m.export(ctx, &copied)
+		})
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (m *member) export(ctx context.Context, line *parser.Line) error {
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := m.exporter.Export(ctx, line)
+	if m.observer != nil {
+		m.observer.ObserveExport(m.name, time.Since(start), err)
+	}
+	if err != nil {
+		m.failed.Add(1)
+		return fmt.Errorf("%s: %w", m.name, err)
+	}
+	m.succeeded.Add(1)
+	return nil
+}
+
+// Start starts the exporters in the order they were added
+func (e *Exporters) Start(ctx context.Context) error {
+	for _, m := range e.exporters {
+		if err := m.exporter.Start(ctx); err != nil {
+			return fmt.Errorf("%s: %w", m.name, err)
 		}
 	}
 	return nil
 }
 
+// Flush flushes the exporters in the reverse order they were added, so that the
+// aggregation stages added after the sinks they feed are flushed before them
+func (e *Exporters) Flush(ctx context.Context) error {
+	var errs []error
+	for i := len(e.exporters) - 1; i >= 0; i-- {
+		m := e.exporters[i]
+		if err := m.exporter.Flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the exporters in the reverse order they were added
+func (e *Exporters) Close(ctx context.Context) error {
+	var errs []error
+	for i := len(e.exporters) - 1; i >= 0; i-- {
+		m := e.exporters[i]
+		if err := m.exporter.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Check checks the exporters which implement Checker concurrently and returns
+// the problems joined together
+func (e *Exporters) Check(ctx context.Context) error {
+	errs := make([]error, len(e.exporters))
+	var wg sync.WaitGroup
+	for i, m := range e.exporters {
+		checker, ok := as[Checker](m.exporter)
+		if !ok {
+			continue
+		}
+		wg.Go(func() {
+			if err := checker.Check(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", m.name, err)
+			}
+		})
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// AddExporter adds an exporter without timeout, named after its type
 func (e *Exporters) AddExporter(exporter Exporter) {
-	e.exporters = append(e.exporters, exporter)
+	e.AddNamedExporter(fmt.Sprintf("%T", exporter), 0, exporter)
+}
+
+// AddNamedExporter adds an exporter whose Export is cancelled after the timeout,
+// 0 means no timeout. The exporter must honor the context cancellation.
+func (e *Exporters) AddNamedExporter(name string, timeout time.Duration, exporter Exporter) {
+	e.exporters = append(e.exporters, &member{
+		name:     name,
+		exporter: exporter,
+		timeout:  timeout,
+		observer: e.observer,
+	})
+}
+
+// Observe tells the observer about every export, it must be called before the
+// first export
+func (e *Exporters) Observe(observer ExportObserver) {
+	e.observer = observer
+	for _, m := range e.exporters {
+		m.observer = observer
+	}
+}
+
+// Queues returns the exporters which hold lines, by name
+func (e *Exporters) Queues() map[string]Queue {
+	queues := make(map[string]Queue)
+	for _, m := range e.exporters {
+		if queue, ok := as[Queue](m.exporter); ok {
+			queues[m.name] = queue
+		}
+	}
+	return queues
+}
+
+// Stats returns the export counters of every exporter
+func (e *Exporters) Stats() []Stats {
+	stats := make([]Stats, len(e.exporters))
+	for i, m := range e.exporters {
+		stats[i] = Stats{
+			Name:      m.name,
+			Succeeded: m.succeeded.Load(),
+			Failed:    m.failed.Load(),
+		}
+	}
+	return stats
 }
@@ -0,0 +1,64 @@
+package exporters
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+type funcExporter func(ctx context.Context, line *parser.Line) error
+
+func (f funcExporter) Start(ctx context.Context) error { return nil }
+func (f funcExporter) Flush(ctx context.Context) error { return nil }
+func (f funcExporter) Close(ctx context.Context) error { return nil }
+
+func (f funcExporter) Export(ctx context.Context, line *parser.Line) error {
+	return f(ctx, line)
+}
+
+func TestExportersIsolateFailures(t *testing.T) {
+	errDown := errors.New("endpoint down")
+	var received atomic.Int64
+
+	fanOut := New()
+	fanOut.AddNamedExporter("down", 0, funcExporter(func(ctx context.Context, line *parser.Line) error {
+		return errDown
+	}))
+	fanOut.AddNamedExporter("slow", 10*time.Millisecond, funcExporter(func(ctx context.Context, line *parser.Line) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	fanOut.AddNamedExporter("healthy", 0, funcExporter(func(ctx context.Context, line *parser.Line) error {
+		// Every exporter gets its own copy of the line
+		line.CostEstimate = &parser.CostEstimate{}
+		received.Add(1)
+		return nil
+	}))
+
+	line := costLine(100)
+	err := fanOut.Export(context.Background(), line)
+	if !errors.Is(err, errDown) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Export() error = %v, want both failures joined", err)
+	}
+	if received.Load() != 1 {
+		t.Errorf("healthy exporter received %d lines, want 1", received.Load())
+	}
+	if line.CostEstimate != nil {
+		t.Error("the line of the caller was modified")
+	}
+
+	want := []Stats{
+		{Name: "down", Failed: 1},
+		{Name: "slow", Failed: 1},
+		{Name: "healthy", Succeeded: 1},
+	}
+	for i, stats := range fanOut.Stats() {
+		if stats != want[i] {
+			t.Errorf("Stats()[%d] = %+v, want %+v", i, stats, want[i])
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package exporters
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+// Projection is the list of the MessageLine fields an exporter sends, as their
+// JSON names. Nested fields are separated by dots, e.g. "cost_details.event_cost".
+// An empty projection sends the whole MessageLine.
+type Projection []string
+
+// Apply returns the exported representation of the line
+func (p Projection) Apply(line *parser.Line) (any, error) {
+	message := line.ToMessageLine()
+	if len(p) == 0 {
+		return message, nil
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	var full map[string]any
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]any, len(p))
+	for _, field := range p {
+		copyField(projected, full, strings.Split(field, "."))
+	}
+	return projected, nil
+}
+
+// copyField copies the value at path from src to dst, creating the intermediate
+// objects. A path which does not exist in src is skipped.
+func copyField(dst map[string]any, src map[string]any, path []string) {
+	value, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = value
+		return
+	}
+
+	nested, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	child, ok := dst[path[0]].(map[string]any)
+	if !ok {
+		child = make(map[string]any)
+		dst[path[0]] = child
+	}
+	copyField(child, nested, path[1:])
+}
+
+// Validate checks that the fields exist in a MessageLine, down to their last
+// segment. A field can only be nested in an object, i.e. a struct or a map.
+func (p Projection) Validate() error {
+	for _, field := range p {
+		if !validPath(reflect.TypeOf(parser.MessageLine{}), strings.Split(field, ".")) {
+			return fmt.Errorf("%w: %q", ErrUnknownField, field)
+		}
+	}
+	return nil
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// validPath reports whether path names a field of the JSON encoding of t
+func validPath(t reflect.Type, path []string) bool {
+	for _, name := range path {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if name == "" || t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+			// e.g. a time.Time, which is encoded as a string
+			return false
+		}
+		switch t.Kind() {
+		case reflect.Map:
+			// The keys are only known at runtime
+			t = t.Elem()
+		case reflect.Struct:
+			field, ok := jsonFields(t)[name]
+			if !ok {
+				return false
+			}
+			t = field
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// jsonFields returns the types of the fields of a struct type by their JSON
+// names, including the fields of the embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case name == "-" || !field.IsExported():
+			continue
+		case name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct:
+			for nested, nestedType := range jsonFields(field.Type) {
+				fields[nested] = nestedType
+			}
+			continue
+		case name == "":
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
@@ -0,0 +1,82 @@
+package exporters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func costLine(cost int) *parser.Line {
+	return &parser.Line{
+		TraceID:      "8400687f8dbbef675fb7b6e4661f461d",
+		SpanName:     "SequencerClient.sendAsync",
+		Synchronizer: "global-domain::1220be58c29e",
+		Kind:         parser.KindEventCost,
+		CostDetails:  &parser.EventCostDetails{EventCost: cost, CostMultiplier: 4},
+	}
+}
+
+func TestProjection(t *testing.T) {
+	projected, err := Projection{"trace_id", "cost_details.event_cost", "traffic_state"}.Apply(costLine(14097))
+	if err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	data, _ := json.Marshal(projected)
+	want := `{"cost_details":{"event_cost":14097},"trace_id":"8400687f8dbbef675fb7b6e4661f461d"}`
+	if string(data) != want {
+		t.Errorf("Apply() = %s, want %s", data, want)
+	}
+
+	if err := (Projection{"trace_id", "cost_estimate.usd", "submission"}).Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+	if err := (Projection{"cost_details.event_cost", "cost_details.group_to_members_size.3"}).Validate(); err != nil {
+		t.Errorf("Validate() of nested fields error: %v", err)
+	}
+	for _, field := range []string{"traceId", "cost_details.event_cots", "cost_details.", "trace_id.id", "@timestamp.seconds", "cost_details.envelopes_cost.final_cost"} {
+		if err := (Projection{field}).Validate(); !errors.Is(err, ErrUnknownField) {
+			t.Errorf("Validate(%q) error = %v, want %v", field, err, ErrUnknownField)
+		}
+	}
+}
+
+func TestFilteredFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines.jsonl")
+	file := NewFileExporter(path, Projection{"trace_id", "cost_details.event_cost"})
+	// Only Close writes the lines
+	file.FlushInterval = 0
+	exporter := NewFiltered(filter.Filter{MinCost: 1000, SpanName: "SequencerClient.sendAsync"}, file)
+
+	ctx := context.Background()
+	if err := exporter.Start(ctx); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	for _, cost := range []int{500, 5000, 20000} {
+		if err := exporter.Export(ctx, costLine(cost)); err != nil {
+			t.Fatalf("Export() error: %v", err)
+		}
+	}
+	// The lines are buffered until Flush
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("file content before Flush = %s, want it empty", data)
+	}
+	if err := exporter.Close(ctx); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"event_cost":5000`) || !strings.Contains(lines[1], `"event_cost":20000`) {
+		t.Errorf("file content = %s, want the two large events", data)
+	}
+}
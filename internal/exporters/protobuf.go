@@ -0,0 +1,197 @@
+package exporters
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+	cantcostv1 "github.com/DLC-link/cantcost/proto/cantcost/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// marshalProtobuf encodes the lines as a cantcost.v1.ExportRequest, see
+// proto/cantcost/v1/export.proto
+func marshalProtobuf(lines []any) ([]byte, error) {
+	request := &cantcostv1.ExportRequest{
+		Count: uint32(len(lines)),
+		Lines: make([]*cantcostv1.Line, 0, len(lines)),
+	}
+	for _, line := range lines {
+		switch l := line.(type) {
+		case *parser.MessageLine:
+			request.Lines = append(request.Lines, protoLine(l))
+		case map[string]any:
+			// A projection has no schema, so it is sent as JSON
+			data, err := json.Marshal(l)
+			if err != nil {
+				return nil, err
+			}
+			request.Lines = append(request.Lines, &cantcostv1.Line{ProjectedJson: data})
+		default:
+			return nil, fmt.Errorf("cannot encode %T as protobuf", line)
+		}
+	}
+	// The map entries are sorted, so that the encoding is deterministic
+	return proto.MarshalOptions{Deterministic: true}.Marshal(request)
+}
+
+func protoLine(l *parser.MessageLine) *cantcostv1.Line {
+	line := &cantcostv1.Line{
+		Timestamp:    unixNano(l.Timestamp),
+		Message:      l.Message,
+		LoggerName:   l.LoggerName,
+		ThreadName:   l.ThreadName,
+		Level:        l.Level,
+		SpanId:       l.SpanID,
+		SpanParentId: l.SpanParentID,
+		TraceId:      l.TraceID,
+		SpanName:     l.SpanName,
+		Participant:  l.Participant,
+		Synchronizer: l.Synchronizer,
+		Kind:         string(l.Kind),
+	}
+
+	if d := l.CostDetails; d != nil {
+		details := &cantcostv1.EventCostDetails{
+			EventCost:        int64(d.EventCost),
+			CostMultiplier:   int64(d.CostMultiplier),
+			ValidationErrors: d.ValidationErrors,
+		}
+		if len(d.GroupToMembersSize) > 0 {
+			details.GroupToMembersSize = make(map[int64]int64, len(d.GroupToMembersSize))
+			for group, size := range d.GroupToMembersSize {
+				details.GroupToMembersSize[int64(group)] = int64(size)
+			}
+		}
+		for _, envelope := range d.EnvelopesCost {
+			e := &cantcostv1.EnvelopeCostDetails{
+				WriteCost: int64(envelope.WriteCost),
+				ReadCost:  int64(envelope.ReadCost),
+				FinalCost: int64(envelope.FinalCost),
+			}
+			for _, recipient := range envelope.Recipients {
+				e.Recipients = append(e.Recipients, &cantcostv1.Recipient{
+					Type:    recipient.Type,
+					Member:  recipient.Member,
+					GroupId: int64(recipient.GroupID),
+				})
+			}
+			details.EnvelopesCost = append(details.EnvelopesCost, e)
+		}
+		line.CostDetails = details
+	}
+	if s := l.TrafficState; s != nil {
+		line.TrafficState = &cantcostv1.TrafficState{
+			ExtraTrafficPurchased: int64(s.ExtraTrafficPurchased),
+			ExtraTrafficConsumed:  int64(s.ExtraTrafficConsumed),
+			BaseTrafficRemainder:  int64(s.BaseTrafficRemainder),
+			LastConsumedCost:      int64(s.LastConsumedCost),
+			Timestamp:             unixNano(s.Timestamp),
+			Serial:                int64(s.Serial),
+		}
+	}
+	if r := l.TrafficReceipt; r != nil {
+		line.TrafficReceipt = &cantcostv1.TrafficReceipt{
+			ConsumedCost:         int64(r.ConsumedCost),
+			ExtraTrafficConsumed: int64(r.ExtraTrafficConsumed),
+			BaseTrafficRemainder: int64(r.BaseTrafficRemainder),
+		}
+	}
+	if p := l.TrafficPurchased; p != nil {
+		line.TrafficPurchased = &cantcostv1.TrafficPurchased{
+			Member:                p.Member,
+			Serial:                int64(p.Serial),
+			ExtraTrafficPurchased: int64(p.ExtraTrafficPurchased),
+			SequencingTimestamp:   unixNano(p.SequencingTimestamp),
+		}
+	}
+	if r := l.TrafficRejection; r != nil {
+		line.TrafficRejection = &cantcostv1.TrafficRejection{
+			Code:      r.Code,
+			Member:    r.Member,
+			Required:  int64(r.Required),
+			Available: int64(r.Available),
+		}
+	}
+	if s := l.TraceSummary; s != nil {
+		line.TraceSummary = &cantcostv1.TraceSummary{
+			TraceId:            s.TraceID,
+			EventCount:         int64(s.EventCount),
+			TotalCost:          int64(s.TotalCost),
+			EnvelopeCount:      int64(s.EnvelopeCount),
+			DistinctRecipients: int64(s.DistinctRecipients),
+			FirstTimestamp:     unixNano(s.FirstTimestamp),
+			LastTimestamp:      unixNano(s.LastTimestamp),
+		}
+	}
+	if c := l.CounterpartyCost; c != nil {
+		line.CounterpartyCost = &cantcostv1.CounterpartyCost{
+			Counterparty:   c.Counterparty,
+			Policy:         c.Policy,
+			WindowStart:    unixNano(c.WindowStart),
+			WindowEnd:      unixNano(c.WindowEnd),
+			AttributedCost: c.AttributedCost,
+			EnvelopeCount:  int64(c.EnvelopeCount),
+		}
+	}
+	if r := l.Rollup; r != nil {
+		line.Rollup = &cantcostv1.Rollup{
+			Participant:  r.Participant,
+			Synchronizer: r.Synchronizer,
+			SpanName:     r.SpanName,
+			Window:       r.Window,
+			WindowStart:  unixNano(r.WindowStart),
+			WindowEnd:    unixNano(r.WindowEnd),
+			Count:        int64(r.Count),
+			Sum:          int64(r.Sum),
+			Min:          int64(r.Min),
+			Max:          int64(r.Max),
+			P50:          int64(r.P50),
+			P95:          int64(r.P95),
+			P99:          int64(r.P99),
+		}
+	}
+	if a := l.Anomaly; a != nil {
+		line.Anomaly = &cantcostv1.Anomaly{
+			Key:             a.Key,
+			Metric:          a.Metric,
+			Method:          a.Method,
+			Value:           a.Value,
+			Mean:            a.Mean,
+			StdDev:          a.StdDev,
+			Score:           a.Score,
+			BaselineSamples: int64(a.Baseline),
+		}
+	}
+	if e := l.CostEstimate; e != nil {
+		line.CostEstimate = &cantcostv1.CostEstimate{
+			Traffic:            e.Traffic,
+			Usd:                e.USD,
+			Cc:                 e.CC,
+			UsdPerMb:           e.USDPerMB,
+			UsdPerCc:           e.USDPerCC,
+			RatesEffectiveFrom: unixNano(e.RatesEffectiveFrom),
+		}
+	}
+	if s := l.Submission; s != nil {
+		line.Submission = &cantcostv1.Submission{
+			TraceId:       s.TraceID,
+			CommandId:     s.CommandID,
+			SubmissionId:  s.SubmissionID,
+			User:          s.User,
+			ApplicationId: s.ApplicationID,
+			TemplateId:    s.TemplateID,
+			Choice:        s.Choice,
+		}
+	}
+	return line
+}
+
+// unixNano is 0 for the zero time, as documented in the schema
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
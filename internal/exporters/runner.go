@@ -0,0 +1,36 @@
+package exporters
+
+import (
+	"context"
+)
+
+// Runner runs the background loop of an exporter between its Start and Close.
+// The loop gets a context which is only cancelled by Stop, so it outlives the
+// context of Start, e.g. to keep flushing during a graceful shutdown.
+type Runner struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (r *Runner) Start(ctx context.Context, run func(ctx context.Context)) {
+	ctx, r.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		run(ctx)
+	}()
+}
+
+// Stop cancels the loop and waits for it to return, or for ctx to be done
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,186 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+// RequestTemplate renders the requests of the HTTP exporter with text/template.
+// The method, the URL and the header values are rendered for every line with its
+// MessageLine as data, and the lines which render the same request are posted
+// together. The body is rendered for each of these batches with an HTTPRequest as
+// data, it replaces the Encoding of the exporter.
+type RequestTemplate struct {
+	method  *template.Template
+	url     *template.Template
+	headers map[string]*template.Template
+	// body is nil when the body is marshalled with the Encoding
+	body *template.Template
+}
+
+// request is the rendered method, URL and headers of a line
+type request struct {
+	method string
+	url    string
+	header http.Header
+}
+
+// key identifies the request, the lines with the same key are posted together
+func (r request) key() string {
+	var b strings.Builder
+	b.WriteString(r.method + " " + r.url)
+	names := make([]string, 0, len(r.header))
+	for name := range r.header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		b.WriteString("\n" + name + ": " + r.header.Get(name))
+	}
+	return b.String()
+}
+
+// templateFuncs are the functions available to the templates, on top of the
+// text/template builtins
+var templateFuncs = template.FuncMap{
+	// json encodes the value, e.g. to quote a string inside a JSON body
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// NewRequestTemplate parses the templates. An empty method is POST and an empty
+// body is marshalled with the Encoding of the exporter.
+func NewRequestTemplate(method string, url string, headers map[string]string, body string) (*RequestTemplate, error) {
+	if method == "" {
+		method = http.MethodPost
+	}
+	t := &RequestTemplate{headers: make(map[string]*template.Template, len(headers))}
+
+	var err error
+	if t.method, err = parseTemplate("method", method); err != nil {
+		return nil, err
+	}
+	if t.url, err = parseTemplate("url", url); err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		if t.headers[name], err = parseTemplate("headers."+name, value); err != nil {
+			return nil, err
+		}
+	}
+	if body != "" {
+		if t.body, err = parseTemplate("body", body); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func parseTemplate(name string, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return t, nil
+}
+
+// Validate renders the templates with a sample line, in which every optional
+// record is set, so that a misspelt field fails at startup rather than on the
+// first export. The rendered method and URL must be valid.
+func (t *RequestTemplate) Validate(fields Projection) error {
+	line := sampleLine()
+	req, err := t.request(line.ToMessageLine())
+	if err != nil {
+		return err
+	}
+	if _, err := http.NewRequest(req.method, req.url, nil); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	if u, err := url.Parse(req.url); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url: must render an absolute http or https URL, got %q", ErrInvalidTemplate, req.url)
+	}
+
+	if t.body == nil {
+		return nil
+	}
+	projected, err := fields.Apply(&line)
+	if err != nil {
+		return err
+	}
+	_, err = t.render([]any{projected})
+	return err
+}
+
+// request renders the method, the URL and the headers of the line
+func (t *RequestTemplate) request(line *parser.MessageLine) (request, error) {
+	method, err := execute(t.method, line)
+	if err != nil {
+		return request{}, err
+	}
+	url, err := execute(t.url, line)
+	if err != nil {
+		return request{}, err
+	}
+	header := make(http.Header, len(t.headers))
+	for name, value := range t.headers {
+		rendered, err := execute(value, line)
+		if err != nil {
+			return request{}, err
+		}
+		header.Set(name, rendered)
+	}
+	return request{method: strings.ToUpper(strings.TrimSpace(method)), url: strings.TrimSpace(url), header: header}, nil
+}
+
+// render renders the body of the lines, which must be set
+func (t *RequestTemplate) render(lines []any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.body.Execute(&buf, HTTPRequest{Count: len(lines), Lines: lines}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func execute(t *template.Template, data any) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return b.String(), nil
+}
+
+// sampleLine returns a line in which every pointer to a struct is set, recursively
+func sampleLine() parser.Line {
+	var line parser.Line
+	fillPointers(reflect.ValueOf(&line).Elem())
+	return line
+}
+
+func fillPointers(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct:
+			field.Set(reflect.New(field.Type().Elem()))
+			fillPointers(field.Elem())
+		case field.Kind() == reflect.Struct:
+			fillPointers(field)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package exporters
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestHTTPTemplate(t *testing.T) {
+	var mutex sync.Mutex
+	bodies := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPut || r.Header.Get("X-Synchronizer") == "" {
+			t.Errorf("request = %s with headers %v", r.Method, r.Header)
+		}
+		mutex.Lock()
+		bodies[r.URL.Path] = string(body)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	template, err := NewRequestTemplate(
+		"put",
+		server.URL+"/{{ .Synchronizer }}",
+		map[string]string{"X-Synchronizer": "{{ .Synchronizer }}"},
+		`{"text":{{ printf "%d lines, first costs %d" .Count (index .Lines 0).CostDetails.EventCost | json }}}`,
+	)
+	if err != nil {
+		t.Fatalf("NewRequestTemplate() error: %v", err)
+	}
+	if err := template.Validate(nil); err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+
+	exporter := NewHTTPExporter(server.URL, "", 3, 0, nil)
+	exporter.Template = template
+	ctx := context.Background()
+	for i, synchronizer := range []string{"global", "private", "global"} {
+		line := costLine(100 * (i + 1))
+		line.Synchronizer = synchronizer
+		if err := exporter.Export(ctx, line); err != nil {
+			t.Fatalf("Export() error: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		"/global":  `{"text":"2 lines, first costs 100"}`,
+		"/private": `{"text":"1 lines, first costs 200"}`,
+	}
+	for path, body := range want {
+		if bodies[path] != body {
+			t.Errorf("body of %s = %s, want %s", path, bodies[path], body)
+		}
+	}
+}
+
+func TestRequestTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+	}{
+		{name: "unknown field", url: "https://example.com/{{ .Synchroniser }}"},
+		{name: "nested unknown field", url: "https://example.com", body: "{{ range .Lines }}{{ .CostDetails.Cost }}{{ end }}"},
+		{name: "invalid method", method: "NOT VALID", url: "https://example.com"},
+		{name: "relative url", url: "{{ .Synchronizer }}/costs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := NewRequestTemplate(tt.method, tt.url, nil, tt.body)
+			if err == nil {
+				err = template.Validate(nil)
+			}
+			if !errors.Is(err, ErrInvalidTemplate) {
+				t.Errorf("Validate() error = %v, want ErrInvalidTemplate", err)
+			}
+		})
+	}
+}
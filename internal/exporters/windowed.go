@@ -0,0 +1,54 @@
+package exporters
+
+import (
+	"context"
+	"time"
+)
+
+// Windowed is the lifecycle of the stages which hold lines in windows. Between
+// Start and Close, flush is called every interval with all set to false, to
+// export the closed windows. It is called with all set to true on Flush and when
+// the loop stops, to export the open ones too. Embed it and set it with
+// NewWindowed once the stage is built, since flush is usually one of its methods.
+type Windowed struct {
+	interval time.Duration
+	flush    func(ctx context.Context, all bool)
+	runner   Runner
+}
+
+func NewWindowed(interval time.Duration, flush func(ctx context.Context, all bool)) Windowed {
+	return Windowed{interval: interval, flush: flush}
+}
+
+// Start runs the periodic flush in the background until Close
+func (w *Windowed) Start(ctx context.Context) error {
+	w.runner.Start(ctx, w.run)
+	return nil
+}
+
+// Flush exports all the windows, whether they are closed or not
+func (w *Windowed) Flush(ctx context.Context) error {
+	w.flush(ctx, true)
+	return nil
+}
+
+// Close stops the periodic flush
+func (w *Windowed) Close(ctx context.Context) error {
+	return w.runner.Stop(ctx)
+}
+
+func (w *Windowed) run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Stop cancelled the loop, the open windows still reach the exporters
+			w.flush(context.WithoutCancel(ctx), true)
+			return
+		case <-ticker.C:
+			w.flush(ctx, false)
+		}
+	}
+}
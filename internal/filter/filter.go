@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+// Filter selects parsed lines, the zero value matches every line
+type Filter struct {
+	From         time.Time     `json:"from"`
+	To           time.Time     `json:"to"`
+	Kinds        []parser.Kind `json:"kinds"`
+	Synchronizer string        `json:"synchronizer"`
+	// MinCost is the minimum event cost, lines without cost details do not match it
+	MinCost   int    `json:"min_cost"`
+	Recipient string `json:"recipient"`
+	TraceID   string `json:"trace_id"`
+	SpanName  string `json:"span_name"`
+	// Level is the log level, compared case-insensitively
+	Level string `json:"level"`
+}
+
+func (f *Filter) Match(line *parser.Line) bool {
+	if !f.From.IsZero() && line.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !line.Timestamp.Before(f.To) {
+		return false
+	}
+	if len(f.Kinds) > 0 && !f.matchKind(line.Kind) {
+		return false
+	}
+	if f.Synchronizer != "" && line.Synchronizer != f.Synchronizer {
+		return false
+	}
+	if f.TraceID != "" && line.TraceID != f.TraceID {
+		return false
+	}
+	if f.SpanName != "" && line.SpanName != f.SpanName {
+		return false
+	}
+	if f.Level != "" && !strings.EqualFold(line.Level, f.Level) {
+		return false
+	}
+	if f.MinCost > 0 && (line.CostDetails == nil || line.CostDetails.EventCost < f.MinCost) {
+		return false
+	}
+	if f.Recipient != "" && !hasRecipient(line, f.Recipient) {
+		return false
+	}
+	return true
+}
+
+func (f *Filter) matchKind(kind parser.Kind) bool {
+	for _, k := range f.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRecipient(line *parser.Line, recipient string) bool {
+	if line.CostDetails == nil {
+		return false
+	}
+	for _, envelope := range line.CostDetails.EnvelopesCost {
+		for _, r := range envelope.Recipients {
+			if r.ID() == recipient {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FromQuery builds the filter from the query parameters of a request:
+// from, to (RFC3339), kind (repeatable), synchronizer, min_cost, recipient,
+// trace_id, span_name and level.
+func FromQuery(query url.Values) (Filter, error) {
+	var f Filter
+	var err error
+
+	if f.From, err = parseTime(query, "from"); err != nil {
+		return Filter{}, err
+	}
+	if f.To, err = parseTime(query, "to"); err != nil {
+		return Filter{}, err
+	}
+	for _, kind := range query["kind"] {
+		f.Kinds = append(f.Kinds, parser.Kind(kind))
+	}
+	f.Synchronizer = query.Get("synchronizer")
+	f.Recipient = query.Get("recipient")
+	f.TraceID = query.Get("trace_id")
+	f.SpanName = query.Get("span_name")
+	f.Level = query.Get("level")
+	if v := query.Get("min_cost"); v != "" {
+		if f.MinCost, err = strconv.Atoi(v); err != nil {
+			return Filter{}, fmt.Errorf("invalid min_cost parameter: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+func parseTime(query url.Values, name string) (time.Time, error) {
+	v := query.Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s parameter: %w", name, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,8 @@
+package health
+
+import "errors"
+
+var (
+	ErrNotAttached = errors.New("log stream not attached")
+	ErrStale       = errors.New("log stream stale")
+)
@@ -0,0 +1,113 @@
+package health
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Monitor follows the log streams of the targets to tell whether cantcost is
+// ready and alive. A stream is stuck when no line was read for StaleAfter
+// while its pod is Running, e.g. after a silent network failure; a pod which is
+// not Running is expected to be quiet.
+type Monitor struct {
+	// StaleAfter is 0 when a quiet stream is not a failure
+	StaleAfter time.Duration `json:"stale_after"`
+
+	targets map[string]*targetState
+	mutex   *sync.Mutex
+	now     func() time.Time
+}
+
+type targetState struct {
+	attached   bool
+	podRunning bool
+	lastLineAt time.Time
+}
+
+func New(staleAfter time.Duration, targets ...string) *Monitor {
+	m := &Monitor{
+		StaleAfter: staleAfter,
+		targets:    make(map[string]*targetState),
+		mutex:      &sync.Mutex{},
+		now:        time.Now,
+	}
+	for _, target := range targets {
+		m.targets[target] = &targetState{}
+	}
+	return m
+}
+
+// Attached records that the log stream of the target's pod is open
+func (m *Monitor) Attached(target string, podRunning bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.targets[target] = &targetState{
+		attached:   true,
+		podRunning: podRunning,
+		lastLineAt: m.now(),
+	}
+}
+
+// Detached records that the log stream of the target ended
+func (m *Monitor) Detached(target string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if state, ok := m.targets[target]; ok {
+		state.attached = false
+	}
+}
+
+// PodPhaseChanged records whether the target's pod is Running. The quiet period
+// starts over when the pod turns Running, since it was expected to be quiet.
+func (m *Monitor) PodPhaseChanged(target string, podRunning bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if state, ok := m.targets[target]; ok {
+		if podRunning && !state.podRunning {
+			state.lastLineAt = m.now()
+		}
+		state.podRunning = podRunning
+	}
+}
+
+// LineRead records that a log line of the target was read
+func (m *Monitor) LineRead(target string, loggedAt time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if state, ok := m.targets[target]; ok {
+		state.lastLineAt = m.now()
+	}
+}
+
+// Ready fails if the log stream of a target is not attached
+func (m *Monitor) Ready() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for target, state := range m.targets {
+		if !state.attached {
+			return fmt.Errorf("%w: %s", ErrNotAttached, target)
+		}
+	}
+	return nil
+}
+
+// Live fails if the log stream of a Running pod is stale
+func (m *Monitor) Live() error {
+	if m.StaleAfter <= 0 {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := m.now()
+	for target, state := range m.targets {
+		if !state.attached || !state.podRunning {
+			continue
+		}
+		if quiet := now.Sub(state.lastLineAt); quiet > m.StaleAfter {
+			return fmt.Errorf("%w: %s read no line for %s", ErrStale, target, quiet.Round(time.Second))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitor(t *testing.T) {
+	now := time.Date(2025, 12, 3, 17, 0, 0, 0, time.UTC)
+	m := New(time.Minute, "canton/participant-1", "canton/participant-2")
+	m.now = func() time.Time { return now }
+
+	if err := m.Ready(); !errors.Is(err, ErrNotAttached) {
+		t.Errorf("Ready() before attach = %v, want %v", err, ErrNotAttached)
+	}
+	m.Attached("canton/participant-1", true)
+	m.Attached("canton/participant-2", false)
+	if err := m.Ready(); err != nil {
+		t.Errorf("Ready() error: %v", err)
+	}
+
+	now = now.Add(30 * time.Second)
+	m.LineRead("canton/participant-1", now)
+	now = now.Add(45 * time.Second)
+	if err := m.Live(); err != nil {
+		t.Errorf("Live() after a recent line: %v", err)
+	}
+
+	// participant-2 is not Running, its silence is expected
+	now = now.Add(time.Minute)
+	if err := m.Live(); !errors.Is(err, ErrStale) {
+		t.Errorf("Live() after a quiet minute = %v, want %v", err, ErrStale)
+	}
+
+	m.Detached("canton/participant-1")
+	if err := m.Live(); err != nil {
+		t.Errorf("Live() after detach: %v", err)
+	}
+	if err := m.Ready(); !errors.Is(err, ErrNotAttached) {
+		t.Errorf("Ready() after detach = %v, want %v", err, ErrNotAttached)
+	}
+}
+
+func TestMonitorPodPhaseChanged(t *testing.T) {
+	now := time.Date(2025, 12, 3, 17, 0, 0, 0, time.UTC)
+	m := New(time.Minute, "canton/participant-1")
+	m.now = func() time.Time { return now }
+
+	// The pod was Pending when the stream was attached
+	m.Attached("canton/participant-1", false)
+	now = now.Add(5 * time.Minute)
+	m.PodPhaseChanged("canton/participant-1", true)
+	if err := m.Live(); err != nil {
+		t.Errorf("Live() when the pod turns Running: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := m.Live(); !errors.Is(err, ErrStale) {
+		t.Errorf("Live() after two quiet minutes = %v, want %v", err, ErrStale)
+	}
+
+	m.PodPhaseChanged("canton/participant-1", false)
+	if err := m.Live(); err != nil {
+		t.Errorf("Live() when the pod stops Running: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/DLC-link/cantcost/internal/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// New returns the client of an HTTP exporter: http.DefaultClient unless mutual
+// TLS or OAuth2 is configured. The OAuth2 tokens are requested with the same
+// TLS settings as the exports, and renewed RefreshBefore their expiry.
+func New(tlsConfig config.TLS, oauth config.OAuth2) (*http.Client, error) {
+	if !tlsConfig.Enabled() && !oauth.Enabled() {
+		return http.DefaultClient, nil
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if tlsConfig.Enabled() {
+		reloading, err := newReloadingTransport(tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		transport = reloading
+	}
+
+	if oauth.Enabled() {
+		secret, err := config.ReadSecret(oauth.ClientSecret, oauth.ClientSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		credentials := clientcredentials.Config{
+			ClientID:     oauth.ClientID,
+			ClientSecret: string(secret),
+			TokenURL:     oauth.TokenURL,
+			Scopes:       oauth.Scopes,
+		}
+		// The token source keeps this context to request the next tokens
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: transport})
+		source := oauth2.ReuseTokenSourceWithExpiry(nil, credentials.TokenSource(ctx), oauth.RefreshBefore.Std())
+		transport = &oauth2.Transport{
+			Source: source,
+			Base:   transport,
+		}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
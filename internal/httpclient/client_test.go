@@ -0,0 +1,167 @@
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// authority is a test CA which issues the server and client certificates
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T) *authority {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (a *authority) issue(t *testing.T, serial int64, commonName string) (certPEM []byte, keyPEM []byte) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutualTLSAndOAuth2(t *testing.T) {
+	ca := newAuthority(t)
+	serverCert, serverKey := ca.issue(t, 2, "server")
+	serverPair, err := tls.X509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clients := x509.NewCertPool()
+	clients.AddCert(ca.cert)
+
+	var tokens int
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600}`, tokens)
+	})
+	mux.HandleFunc("POST /ingest", func(w http.ResponseWriter, r *http.Request) {
+		// The client certificate is echoed, to check the reload
+		w.Header().Set("X-Client", r.TLS.PeerCertificates[0].Subject.CommonName)
+		w.Header().Set("X-Authorization", r.Header.Get("Authorization"))
+	})
+	server := httptest.NewUnstartedServer(mux)
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clients,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	tlsConfig := config.TLS{
+		CertFile:       filepath.Join(dir, "tls.crt"),
+		KeyFile:        filepath.Join(dir, "tls.key"),
+		CAFile:         filepath.Join(dir, "ca.crt"),
+		ReloadInterval: config.Duration(time.Minute),
+	}
+	modTime := time.Now().Add(-time.Hour)
+	clientCert, clientKey := ca.issue(t, 3, "client-1")
+	writeFile(t, tlsConfig.CertFile, clientCert, modTime)
+	writeFile(t, tlsConfig.KeyFile, clientKey, modTime)
+	writeFile(t, tlsConfig.CAFile, ca.pem, modTime)
+
+	client, err := New(tlsConfig, config.OAuth2{
+		TokenURL:      server.URL + "/token",
+		ClientID:      "cantcost",
+		ClientSecret:  "secret",
+		RefreshBefore: config.Duration(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	post := func() *http.Response {
+		resp, err := client.Post(server.URL+"/ingest", "application/json", nil)
+		if err != nil {
+			t.Fatalf("Post() error: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := post()
+	if got := resp.Header.Get("X-Client"); got != "client-1" {
+		t.Errorf("client certificate = %q, want client-1", got)
+	}
+	if got := resp.Header.Get("X-Authorization"); got != "Bearer token-1" {
+		t.Errorf("Authorization = %q, want Bearer token-1", got)
+	}
+
+	// The secret is rotated, the new certificate is used once the reload interval elapsed
+	clientCert, clientKey = ca.issue(t, 4, "client-2")
+	writeFile(t, tlsConfig.CertFile, clientCert, modTime.Add(time.Minute))
+	writeFile(t, tlsConfig.KeyFile, clientKey, modTime.Add(time.Minute))
+	reloading := client.Transport.(*oauth2.Transport).Base.(*reloadingTransport)
+	if got := post().Header.Get("X-Client"); got != "client-1" {
+		t.Errorf("client certificate before the reload interval = %q, want client-1", got)
+	}
+	reloading.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	resp = post()
+	if got := resp.Header.Get("X-Client"); got != "client-2" {
+		t.Errorf("client certificate after the rotation = %q, want client-2", got)
+	}
+	if got := resp.Header.Get("X-Authorization"); got != "Bearer token-1" {
+		t.Errorf("Authorization = %q, want the token to be reused", got)
+	}
+}
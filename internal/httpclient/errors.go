@@ -0,0 +1,5 @@
+package httpclient
+
+import "errors"
+
+var ErrNoCertificates = errors.New("no certificate found in CA bundle")
@@ -0,0 +1,134 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/config"
+)
+
+// reloadingTransport uses the certificates of the TLS files, and rebuilds its
+// transport when they change, e.g. when a mounted secret is rotated. The
+// files are checked at most every ReloadInterval, on the next request.
+type reloadingTransport struct {
+	config config.TLS
+
+	transport *http.Transport
+	modTimes  []time.Time
+	checkedAt time.Time
+	mutex     *sync.Mutex
+	now       func() time.Time
+}
+
+func newReloadingTransport(c config.TLS) (*reloadingTransport, error) {
+	t := &reloadingTransport{
+		config: c,
+		mutex:  &sync.Mutex{},
+		now:    time.Now,
+	}
+	modTimes, err := t.statFiles()
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newTransport(c)
+	if err != nil {
+		return nil, err
+	}
+	t.transport = transport
+	t.modTimes = modTimes
+	t.checkedAt = t.now()
+	return t, nil
+}
+
+func (t *reloadingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.current().RoundTrip(req)
+}
+
+// current returns the transport, after reloading it if the files changed. A
+// reload which fails keeps the previous transport, the files may be midway
+// through a rotation.
+func (t *reloadingTransport) current() *http.Transport {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	if now.Sub(t.checkedAt) < t.config.ReloadInterval.Std() {
+		return t.transport
+	}
+	t.checkedAt = now
+
+	modTimes, err := t.statFiles()
+	if err != nil {
+		slog.Error("Failed to check the TLS files", slog.Any("error", err))
+		return t.transport
+	}
+	if slices.EqualFunc(modTimes, t.modTimes, time.Time.Equal) {
+		return t.transport
+	}
+	transport, err := newTransport(t.config)
+	if err != nil {
+		slog.Error("Failed to reload the TLS files, keeping the previous ones", slog.Any("error", err))
+		return t.transport
+	}
+
+	slog.Info("Reloaded the TLS files",
+		slog.String("cert_file", t.config.CertFile),
+		slog.String("ca_file", t.config.CAFile),
+	)
+	t.transport.CloseIdleConnections()
+	t.transport = transport
+	t.modTimes = modTimes
+	return t.transport
+}
+
+// statFiles returns the modification times of the files which are set. The
+// files of a mounted secret are symbolic links, which Stat follows.
+func (t *reloadingTransport) statFiles() ([]time.Time, error) {
+	var modTimes []time.Time
+	for _, file := range []string{t.config.CertFile, t.config.KeyFile, t.config.CAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	return modTimes, nil
+}
+
+func newTransport(c config.TLS) (*http.Transport, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s", ErrNoCertificates, c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
@@ -0,0 +1,230 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics are the self-observability metrics of cantcost, which tell whether it
+// keeps up with the logs, as opposed to the cost metrics it exports. They are
+// served in the Prometheus format and summarized in the logs every
+// SummaryInterval.
+type Metrics struct {
+	// SummaryInterval is 0 when the periodic summary is disabled
+	SummaryInterval time.Duration `json:"summary_interval"`
+
+	registry       *prometheus.Registry
+	linesRead      *prometheus.CounterVec
+	linesMatched   prometheus.Counter
+	parseFailures  *prometheus.CounterVec
+	exportAttempts *prometheus.CounterVec
+	exportSuccess  *prometheus.CounterVec
+	exportFailures *prometheus.CounterVec
+	exportDuration *prometheus.HistogramVec
+	reconnects     *prometheus.CounterVec
+	lag            *prometheus.GaugeVec
+
+	// attached are the targets whose stream was attached once, so that the next
+	// attachments are reconnections
+	attached map[string]bool
+	mutex    *sync.Mutex
+	totals   totals
+}
+
+// totals are the counters of the periodic summary
+type totals struct {
+	linesRead      atomic.Int64
+	linesMatched   atomic.Int64
+	parseFailures  atomic.Int64
+	exportFailures atomic.Int64
+	reconnects     atomic.Int64
+	// lag is the last lag, in nanoseconds
+	lag atomic.Int64
+}
+
+func New(summaryInterval time.Duration) *Metrics {
+	m := &Metrics{
+		SummaryInterval: summaryInterval,
+		registry:        prometheus.NewRegistry(),
+		linesRead: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cantcost_lines_read_total",
+			Help: "Log lines read from the target pods.",
+		}, []string{"target"}),
+		linesMatched: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cantcost_lines_matched_total",
+			Help: "Log lines matching the cost and traffic markers, which are parsed.",
+		}),
+		parseFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cantcost_parse_failures_total",
+			Help: "Matching log lines which could not be parsed, by reason.",
+		}, []string{"reason"}),
+		exportAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cantcost_export_attempts_total",
+			Help: "Lines passed to each exporter.",
+		}, []string{"exporter"}),
+		exportSuccess: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cantcost_export_successes_total",
+			Help: "Lines exported successfully by each exporter.",
+		}, []string{"exporter"}),
+		exportFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cantcost_export_failures_total",
+			Help: "Lines which each exporter failed to export.",
+		}, []string{"exporter"}),
+		exportDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cantcost_export_duration_seconds",
+			Help:    "Duration of the export of a line by each exporter, including the posting of a full batch.",
+			Buckets: []float64{.0001, .001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"exporter"}),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cantcost_stream_reconnects_total",
+			Help: "Reconnections of the log stream of each target.",
+		}, []string{"target"}),
+		lag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cantcost_log_lag_seconds",
+			Help: "Delay between the Docker timestamp of the last line read and its reading.",
+		}, []string{"target"}),
+		attached: make(map[string]bool),
+		mutex:    &sync.Mutex{},
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.linesRead,
+		m.linesMatched,
+		m.parseFailures,
+		m.exportAttempts,
+		m.exportSuccess,
+		m.exportFailures,
+		m.exportDuration,
+		m.reconnects,
+		m.lag,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus format:
+//
+//	GET /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Attached counts the reconnections, see catcher.Observer
+func (m *Metrics) Attached(target string, podRunning bool) {
+	m.mutex.Lock()
+	reconnected := m.attached[target]
+	m.attached[target] = true
+	m.mutex.Unlock()
+
+	if reconnected {
+		m.reconnects.WithLabelValues(target).Inc()
+		m.totals.reconnects.Add(1)
+	}
+}
+
+func (m *Metrics) Detached(target string) {}
+
+func (m *Metrics) PodPhaseChanged(target string, podRunning bool) {}
+
+// LineRead counts the line and measures its lag, see catcher.Observer
+func (m *Metrics) LineRead(target string, loggedAt time.Time) {
+	m.linesRead.WithLabelValues(target).Inc()
+	m.totals.linesRead.Add(1)
+	if !loggedAt.IsZero() {
+		lag := time.Since(loggedAt)
+		m.lag.WithLabelValues(target).Set(lag.Seconds())
+		m.totals.lag.Store(int64(lag))
+	}
+}
+
+// LineMatched counts a line which is parsed
+func (m *Metrics) LineMatched() {
+	m.linesMatched.Inc()
+	m.totals.linesMatched.Add(1)
+}
+
+// ParseFailed counts a parse failure by the reason of its error
+func (m *Metrics) ParseFailed(err error) {
+	m.parseFailures.WithLabelValues(failureReason(err)).Inc()
+	m.totals.parseFailures.Add(1)
+}
+
+// ObserveExport counts the export and measures its duration, see exporters.ExportObserver
+func (m *Metrics) ObserveExport(exporter string, duration time.Duration, err error) {
+	m.exportAttempts.WithLabelValues(exporter).Inc()
+	m.exportDuration.WithLabelValues(exporter).Observe(duration.Seconds())
+	if err != nil {
+		m.exportFailures.WithLabelValues(exporter).Inc()
+		m.totals.exportFailures.Add(1)
+		return
+	}
+	m.exportSuccess.WithLabelValues(exporter).Inc()
+}
+
+// Queue registers a queue whose depth is read at every scrape
+func (m *Metrics) Queue(name string, depth func() int) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "cantcost_queue_depth",
+		Help:        "Lines held before being exported, e.g. the partial HTTP batches.",
+		ConstLabels: prometheus.Labels{"queue": name},
+	}, func() float64 {
+		return float64(depth())
+	}))
+}
+
+// Run logs a summary every SummaryInterval until the context is done
+func (m *Metrics) Run(ctx context.Context) {
+	if m.SummaryInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(m.SummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.logSummary(ctx)
+		}
+	}
+}
+
+func (m *Metrics) logSummary(ctx context.Context) {
+	slog.InfoContext(ctx, "Processing summary",
+		slog.Int64("lines_read", m.totals.linesRead.Load()),
+		slog.Int64("lines_matched", m.totals.linesMatched.Load()),
+		slog.Int64("parse_failures", m.totals.parseFailures.Load()),
+		slog.Int64("export_failures", m.totals.exportFailures.Load()),
+		slog.Int64("reconnects", m.totals.reconnects.Load()),
+		slog.Duration("lag", time.Duration(m.totals.lag.Load())),
+	)
+}
+
+// failureReason is the label of a parse error
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, parser.ErrNoSeparator):
+		return "no_separator"
+	case errors.Is(err, parser.ErrInvalidDockerTimestamp):
+		return "docker_timestamp"
+	case errors.Is(err, parser.ErrInvalidPayload):
+		return "json_payload"
+	case errors.Is(err, parser.ErrInvalidCostDetails):
+		return "cost_details"
+	case errors.Is(err, parser.ErrInconsistentCostDetails):
+		return "inconsistent_cost_details"
+	}
+	return "other"
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New(0)
+	target := "canton/participant-1"
+
+	m.Attached(target, true)
+	m.LineRead(target, time.Now().Add(-2*time.Second))
+	m.LineMatched()
+	m.ParseFailed(fmt.Errorf("%w: unexpected end of JSON input", parser.ErrInvalidPayload))
+	m.ParseFailed(errors.New("something else"))
+	m.ObserveExport("billing", 20*time.Millisecond, nil)
+	m.ObserveExport("billing", 3*time.Second, errors.New("endpoint down"))
+	m.Detached(target)
+	m.Attached(target, true)
+	m.Queue("exporter/billing", func() int { return 7 })
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+
+	for _, want := range []string{
+		`cantcost_lines_read_total{target="canton/participant-1"} 1`,
+		`cantcost_lines_matched_total 1`,
+		`cantcost_parse_failures_total{reason="json_payload"} 1`,
+		`cantcost_parse_failures_total{reason="other"} 1`,
+		`cantcost_export_attempts_total{exporter="billing"} 2`,
+		`cantcost_export_successes_total{exporter="billing"} 1`,
+		`cantcost_export_failures_total{exporter="billing"} 1`,
+		`cantcost_export_duration_seconds_count{exporter="billing"} 2`,
+		`cantcost_stream_reconnects_total{target="canton/participant-1"} 1`,
+		`cantcost_queue_depth{queue="exporter/billing"} 7`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+	if lag := time.Duration(m.totals.lag.Load()); lag < 2*time.Second {
+		t.Errorf("lag = %s, want at least 2s", lag)
+	}
+}
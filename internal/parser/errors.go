@@ -0,0 +1,11 @@
+package parser
+
+import "errors"
+
+var (
+	ErrNoSeparator             = errors.New("invalid line format: no space separator found")
+	ErrInvalidDockerTimestamp  = errors.New("failed to parse docker timestamp")
+	ErrInvalidPayload          = errors.New("failed to parse JSON payload")
+	ErrInvalidCostDetails      = errors.New("failed to parse EventCostDetails")
+	ErrInconsistentCostDetails = errors.New("inconsistent cost details")
+)
@@ -0,0 +1,40 @@
+package parser
+
+import "strings"
+
+// parseLoggerName extracts the participant and the synchronizer from the Canton
+// logger name, e.g.
+// c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)
+// gives "participant" and "global-domain::1220be58c29e".
+func parseLoggerName(loggerName string) (participant string, synchronizer string) {
+	participant = loggerParam(loggerName, "participant=")
+
+	psid := loggerParam(loggerName, "psid=")
+	if psid == "" {
+		psid = loggerParam(loggerName, "synchronizer=")
+	}
+	psid = strings.TrimPrefix(psid, "IndexedPhysicalSynchronizer(")
+	// The physical synchronizer id is the logical one with the protocol version
+	// suffix, e.g. global-domain::1220be58c29e::34-0
+	parts := strings.SplitN(psid, "::", 3)
+	if len(parts) >= 2 {
+		synchronizer = parts[0] + "::" + parts[1]
+	} else {
+		synchronizer = psid
+	}
+
+	return participant, synchronizer
+}
+
+// loggerParam returns the value of the key in the logger name, up to the next separator
+func loggerParam(loggerName string, key string) string {
+	idx := strings.Index(loggerName, key)
+	if idx == -1 {
+		return ""
+	}
+	value := loggerName[idx+len(key):]
+	if end := strings.IndexAny(value, "/ "); end != -1 {
+		value = value[:end]
+	}
+	return value
+}
@@ -0,0 +1,18 @@
+package parser
+
+// Options are the parser settings
+type Options struct {
+	// IncludeMessage keeps the raw log message in the exported lines
+	IncludeMessage bool
+	// ValidationMode is what happens with inconsistent cost details: ValidationWarn, ValidationDrop or ValidationTag
+	ValidationMode string
+}
+
+var options = Options{
+	ValidationMode: ValidationWarn,
+}
+
+// Configure sets the parser options, it must be called before the lines are processed
+func Configure(o Options) {
+	options = o
+}
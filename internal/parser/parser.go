@@ -1,14 +1,13 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/DLC-link/cantcost/internal/env"
 )
 
 // Recipient represents either a MemberRecipient or MediatorGroupRecipient
@@ -18,6 +17,14 @@ type Recipient struct {
 	GroupID int    `json:"group_id"`
 }
 
+// ID identifies the recipient: the member id for a MemberRecipient, the group for a MediatorGroupRecipient
+func (r Recipient) ID() string {
+	if r.Type == "MediatorGroupRecipient" {
+		return "MediatorGroupRecipient(group = " + strconv.Itoa(r.GroupID) + ")"
+	}
+	return r.Member
+}
+
 // EnvelopeCostDetails represents the cost details for an envelope
 type EnvelopeCostDetails struct {
 	WriteCost  int         `json:"write_cost"`
@@ -32,8 +39,26 @@ type EventCostDetails struct {
 	CostMultiplier     int                   `json:"cost_multiplier"`
 	GroupToMembersSize map[int]int           `json:"group_to_members_size"`
 	EnvelopesCost      []EnvelopeCostDetails `json:"envelopes_cost"`
+
+	// ValidationErrors lists the inconsistencies found by Validate, if tagging is enabled
+	ValidationErrors []string `json:"validation_errors,omitempty"`
 }
 
+// Kind identifies which kind of event a Line carries
+type Kind string
+
+const (
+	KindEventCost        Kind = "event_cost"
+	KindTrafficState     Kind = "traffic_state"
+	KindTrafficReceipt   Kind = "traffic_receipt"
+	KindTrafficPurchased Kind = "traffic_purchased"
+	KindTrafficRejection Kind = "traffic_rejection"
+	KindTraceSummary     Kind = "trace_summary"
+	KindCounterpartyCost Kind = "counterparty_cost"
+	KindRollup           Kind = "rollup"
+	KindAnomaly          Kind = "anomaly"
+)
+
 type Line struct {
 	// DockerTimestamp is the timestamp from the Docker log prefix
 	DockerTimestamp time.Time `json:"-"`
@@ -49,8 +74,27 @@ type Line struct {
 	TraceID      string    `json:"trace-id"`
 	SpanName     string    `json:"span-name"`
 
+	// Parsed from LoggerName
+	Participant  string `json:"-"`
+	Synchronizer string `json:"-"`
+
 	// Parsed from Message
-	CostDetails *EventCostDetails `json:"-"`
+	Kind             Kind              `json:"-"`
+	CostDetails      *EventCostDetails `json:"-"`
+	TrafficState     *TrafficState     `json:"-"`
+	TrafficReceipt   *TrafficReceipt   `json:"-"`
+	TrafficPurchased *TrafficPurchased `json:"-"`
+	TrafficRejection *TrafficRejection `json:"-"`
+
+	// Produced by the aggregation stages
+	TraceSummary     *TraceSummary     `json:"-"`
+	CounterpartyCost *CounterpartyCost `json:"-"`
+	Rollup           *Rollup           `json:"-"`
+	Anomaly          *Anomaly          `json:"-"`
+
+	// Added by the enrichment stages
+	CostEstimate *CostEstimate `json:"-"`
+	Submission   *Submission   `json:"-"`
 }
 
 type MessageLine struct {
@@ -68,84 +112,154 @@ type MessageLine struct {
 	TraceID      string    `json:"trace_id"`
 	SpanName     string    `json:"span_name"`
 
+	// Parsed from LoggerName
+	Participant  string `json:"participant,omitempty"`
+	Synchronizer string `json:"synchronizer,omitempty"`
+
 	// Parsed from Message
-	CostDetails *EventCostDetails `json:"cost_details"`
+	Kind             Kind              `json:"kind,omitempty"`
+	CostDetails      *EventCostDetails `json:"cost_details"`
+	TrafficState     *TrafficState     `json:"traffic_state,omitempty"`
+	TrafficReceipt   *TrafficReceipt   `json:"traffic_receipt,omitempty"`
+	TrafficPurchased *TrafficPurchased `json:"traffic_purchased,omitempty"`
+	TrafficRejection *TrafficRejection `json:"traffic_rejection,omitempty"`
+
+	// Produced by the aggregation stages
+	TraceSummary     *TraceSummary     `json:"trace_summary,omitempty"`
+	CounterpartyCost *CounterpartyCost `json:"counterparty_cost,omitempty"`
+	Rollup           *Rollup           `json:"rollup,omitempty"`
+	Anomaly          *Anomaly          `json:"anomaly,omitempty"`
+
+	// Added by the enrichment stages
+	CostEstimate *CostEstimate `json:"cost_estimate,omitempty"`
+	Submission   *Submission   `json:"submission,omitempty"`
 }
 
 func ProcessLine(line string) (Line, error) {
+	return ProcessBytes([]byte(line))
+}
+
+// ProcessBytes is ProcessLine for the raw bytes of the log stream. The line is
+// not retained, so the caller can reuse its buffer.
+func ProcessBytes(line []byte) (Line, error) {
 	// Find the first space which separates the Docker timestamp from the JSON payload
-	spaceIdx := strings.Index(line, " ")
+	spaceIdx := bytes.IndexByte(line, ' ')
 	if spaceIdx == -1 {
-		return Line{}, fmt.Errorf("invalid line format: no space separator found")
+		return Line{}, ErrNoSeparator
 	}
 
-	dockerTimestampStr := line[:spaceIdx]
+	dockerTimestampStr := string(line[:spaceIdx])
 	jsonPayload := line[spaceIdx+1:]
 
 	// Parse the Docker timestamp (RFC3339Nano format)
 	dockerTimestamp, err := time.Parse(time.RFC3339Nano, dockerTimestampStr)
 	if err != nil {
-		return Line{}, fmt.Errorf("failed to parse docker timestamp: %w", err)
+		return Line{}, fmt.Errorf("%w: %w", ErrInvalidDockerTimestamp, err)
 	}
 
 	// Parse the JSON payload
 	var l Line
-	if err := json.Unmarshal([]byte(jsonPayload), &l); err != nil {
-		return Line{}, fmt.Errorf("failed to parse JSON payload: %w", err)
+	if err := json.Unmarshal(jsonPayload, &l); err != nil {
+		return Line{}, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
 	}
 
 	l.DockerTimestamp = dockerTimestamp
+	l.Participant, l.Synchronizer = parseLoggerName(l.LoggerName)
 
 	// Parse EventCostDetails from the message if present
 	if strings.Contains(l.Message, "EventCostDetails(") {
 		costDetails, err := parseEventCostDetails(l.Message)
 		if err != nil {
-			return Line{}, fmt.Errorf("failed to parse EventCostDetails: %w", err)
+			return Line{}, fmt.Errorf("%w: %w", ErrInvalidCostDetails, err)
 		}
 		l.CostDetails = costDetails
 	}
 
+	// Parse the traffic balance events from the message if present
+	l.TrafficState = parseTrafficState(l.Message)
+	l.TrafficReceipt = parseTrafficReceipt(l.Message)
+	l.TrafficPurchased = parseTrafficPurchased(l.Message)
+	if isTrafficRejection(line) {
+		l.TrafficRejection = parseTrafficRejection(l.Message, l.TrafficState, l.CostDetails)
+	}
+
+	switch {
+	case l.TrafficRejection != nil:
+		l.Kind = KindTrafficRejection
+	case l.CostDetails != nil:
+		l.Kind = KindEventCost
+	case l.TrafficPurchased != nil:
+		l.Kind = KindTrafficPurchased
+	case l.TrafficReceipt != nil:
+		l.Kind = KindTrafficReceipt
+	case l.TrafficState != nil:
+		l.Kind = KindTrafficState
+	}
+
+	if l.Kind == KindEventCost {
+		if err := validateCostDetails(&l); err != nil {
+			return Line{}, err
+		}
+	}
+
 	return l, nil
 }
 
 func (l *Line) ToMessageLine() *MessageLine {
 	message := &MessageLine{
-		DockerTimestamp: l.DockerTimestamp,
-		Timestamp:       l.Timestamp,
-		LoggerName:      l.LoggerName,
-		ThreadName:      l.ThreadName,
-		Level:           l.Level,
-		SpanID:          l.SpanID,
-		SpanParentID:    l.SpanParentID,
-		TraceID:         l.TraceID,
-		SpanName:        l.SpanName,
-		CostDetails:     l.CostDetails,
+		DockerTimestamp:  l.DockerTimestamp,
+		Timestamp:        l.Timestamp,
+		LoggerName:       l.LoggerName,
+		ThreadName:       l.ThreadName,
+		Level:            l.Level,
+		SpanID:           l.SpanID,
+		SpanParentID:     l.SpanParentID,
+		TraceID:          l.TraceID,
+		SpanName:         l.SpanName,
+		Participant:      l.Participant,
+		Synchronizer:     l.Synchronizer,
+		Kind:             l.Kind,
+		CostDetails:      l.CostDetails,
+		TrafficState:     l.TrafficState,
+		TrafficReceipt:   l.TrafficReceipt,
+		TrafficPurchased: l.TrafficPurchased,
+		TrafficRejection: l.TrafficRejection,
+		TraceSummary:     l.TraceSummary,
+		CounterpartyCost: l.CounterpartyCost,
+		Rollup:           l.Rollup,
+		Anomaly:          l.Anomaly,
+		CostEstimate:     l.CostEstimate,
+		Submission:       l.Submission,
 	}
-	if env.GetIncludeMessage() {
+	if options.IncludeMessage {
 		message.Message = l.Message
 	}
 	return message
 }
 
+// Matchers of the EventCostDetails, compiled once. The plain "key = number"
+// parameters do not need a regexp, see intAfter.
+var (
+	groupSizeRe         = regexp.MustCompile(`MediatorGroupRecipient\(group = (\d+)\) -> (\d+)`)
+	memberRecipientRe   = regexp.MustCompile(`MemberRecipient\(([^)]+)\)`)
+	mediatorRecipientRe = regexp.MustCompile(`MediatorGroupRecipient\(group = (\d+)\)`)
+)
+
+const (
+	envelopeCostDetailsPrefix = "EnvelopeCostDetails"
+	recipientsPrefix          = "recipients = "
+)
+
 func parseEventCostDetails(message string) (*EventCostDetails, error) {
 	details := &EventCostDetails{
 		GroupToMembersSize: make(map[int]int),
 	}
 
-	// Extract event cost
-	eventCostRe := regexp.MustCompile(`event cost = (\d+)`)
-	if match := eventCostRe.FindStringSubmatch(message); len(match) > 1 {
-		details.EventCost, _ = strconv.Atoi(match[1])
-	}
-
-	// Extract cost multiplier
-	multiplierRe := regexp.MustCompile(`cost multiplier = (\d+)`)
-	if match := multiplierRe.FindStringSubmatch(message); len(match) > 1 {
-		details.CostMultiplier, _ = strconv.Atoi(match[1])
-	}
+	// Extract event cost and cost multiplier
+	details.EventCost = intAfter(message, "event cost = ")
+	details.CostMultiplier = intAfter(message, "cost multiplier = ")
 
 	// Extract group to members size: MediatorGroupRecipient(group = 0) -> 14
-	groupSizeRe := regexp.MustCompile(`MediatorGroupRecipient\(group = (\d+)\) -> (\d+)`)
 	if match := groupSizeRe.FindStringSubmatch(message); len(match) > 2 {
 		groupID, _ := strconv.Atoi(match[1])
 		size, _ := strconv.Atoi(match[2])
@@ -167,84 +281,75 @@ func parseEnvelopeCostDetails(message string) []EnvelopeCostDetails {
 		return envelopes
 	}
 
-	envelopesSection := message[envelopesStart:]
-
-	// Pattern to match EnvelopeCostDetails
-	// Can be either a single one or a Seq(...)
-	envelopeRe := regexp.MustCompile(`EnvelopeCostDetails\(`)
-	matches := envelopeRe.FindAllStringIndex(envelopesSection, -1)
-
-	for _, match := range matches {
-		startIdx := match[0]
-		envelope := extractEnvelopeCostDetails(envelopesSection[startIdx:])
-		if envelope != nil {
-			envelopes = append(envelopes, *envelope)
+	// Can be either a single EnvelopeCostDetails or a Seq(...) of them
+	section := message[envelopesStart:]
+	envelopes = make([]EnvelopeCostDetails, 0, strings.Count(section, envelopeCostDetailsPrefix+"("))
+	for {
+		startIdx := strings.Index(section, envelopeCostDetailsPrefix+"(")
+		if startIdx == -1 {
+			break
 		}
+		section = section[startIdx+len(envelopeCostDetailsPrefix):]
+		content := extractBalancedParens(section)
+		envelopes = append(envelopes, extractEnvelopeCostDetails(content))
+		section = section[len(content):]
 	}
 
 	return envelopes
 }
 
-func extractEnvelopeCostDetails(s string) *EnvelopeCostDetails {
-	envelope := &EnvelopeCostDetails{}
-
-	// Extract write cost
-	writeCostRe := regexp.MustCompile(`write cost = (\d+)`)
-	if match := writeCostRe.FindStringSubmatch(s); len(match) > 1 {
-		envelope.WriteCost, _ = strconv.Atoi(match[1])
-	}
-
-	// Extract read cost
-	readCostRe := regexp.MustCompile(`read cost = (\d+)`)
-	if match := readCostRe.FindStringSubmatch(s); len(match) > 1 {
-		envelope.ReadCost, _ = strconv.Atoi(match[1])
-	}
-
-	// Extract final cost
-	finalCostRe := regexp.MustCompile(`final cost = (\d+)`)
-	if match := finalCostRe.FindStringSubmatch(s); len(match) > 1 {
-		envelope.FinalCost, _ = strconv.Atoi(match[1])
+// extractEnvelopeCostDetails parses the content of a single EnvelopeCostDetails(...)
+func extractEnvelopeCostDetails(s string) EnvelopeCostDetails {
+	envelope := EnvelopeCostDetails{
+		WriteCost: intAfter(s, "write cost = "),
+		ReadCost:  intAfter(s, "read cost = "),
+		FinalCost: intAfter(s, "final cost = "),
 	}
 
-	// Extract recipients - find the recipients section for this envelope
-	recipientsStart := strings.Index(s, "recipients = ")
+	// Extract recipients, the last parameter of the envelope
+	recipientsStart := strings.Index(s, recipientsPrefix)
 	if recipientsStart == -1 {
 		return envelope
 	}
-
-	// Find where this envelope's recipients end (before the next EnvelopeCostDetails or end of envelope)
-	recipientsSection := s[recipientsStart:]
+	recipientsSection := s[recipientsStart+len(recipientsPrefix):]
 
 	// Check if it's a Seq or a single recipient
-	if strings.HasPrefix(recipientsSection, "recipients = Seq(") {
-		// Find the matching closing paren for the Seq
-		seqContent := extractBalancedParens(recipientsSection[len("recipients = Seq"):])
-		envelope.Recipients = parseRecipients(seqContent)
+	if strings.HasPrefix(recipientsSection, "Seq(") {
+		envelope.Recipients = parseRecipients(extractBalancedParens(recipientsSection[len("Seq"):]))
 	} else {
-		// Single recipient - extract until the closing paren of the envelope
-		singleRecipient := recipientsSection[len("recipients = "):]
-		// Find end - either ) for closing envelope or ,
-		endIdx := strings.Index(singleRecipient, ")")
-		if endIdx != -1 {
-			// Include the closing paren if it's part of the recipient
-			recipientStr := singleRecipient[:endIdx+1]
-			envelope.Recipients = parseRecipients(recipientStr)
-		}
+		envelope.Recipients = parseRecipients(recipientsSection)
 	}
 
 	return envelope
 }
 
+// intAfter returns the number which directly follows the first occurrence of key in s, or 0
+func intAfter(s string, key string) int {
+	idx := strings.Index(s, key)
+	if idx == -1 {
+		return 0
+	}
+	v := 0
+	for _, c := range []byte(s[idx+len(key):]) {
+		if c < '0' || c > '9' {
+			break
+		}
+		v = v*10 + int(c-'0')
+	}
+	return v
+}
+
 func extractBalancedParens(s string) string {
 	if len(s) == 0 || s[0] != '(' {
 		return ""
 	}
 
 	depth := 0
-	for i, c := range s {
-		if c == '(' {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
 			depth++
-		} else if c == ')' {
+		case ')':
 			depth--
 			if depth == 0 {
 				return s[1:i] // Return content inside the parens
@@ -258,28 +363,20 @@ func parseRecipients(s string) []Recipient {
 	var recipients []Recipient
 
 	// Match MemberRecipient(PAR::name::hash...)
-	memberRe := regexp.MustCompile(`MemberRecipient\(([^)]+)\)`)
-	memberMatches := memberRe.FindAllStringSubmatch(s, -1)
-	for _, match := range memberMatches {
-		if len(match) > 1 {
-			recipients = append(recipients, Recipient{
-				Type:   "MemberRecipient",
-				Member: match[1],
-			})
-		}
+	for _, match := range memberRecipientRe.FindAllStringSubmatchIndex(s, -1) {
+		recipients = append(recipients, Recipient{
+			Type:   "MemberRecipient",
+			Member: s[match[2]:match[3]],
+		})
 	}
 
 	// Match MediatorGroupRecipient(group = N)
-	mediatorRe := regexp.MustCompile(`MediatorGroupRecipient\(group = (\d+)\)`)
-	mediatorMatches := mediatorRe.FindAllStringSubmatch(s, -1)
-	for _, match := range mediatorMatches {
-		if len(match) > 1 {
-			groupID, _ := strconv.Atoi(match[1])
-			recipients = append(recipients, Recipient{
-				Type:    "MediatorGroupRecipient",
-				GroupID: groupID,
-			})
-		}
+	for _, match := range mediatorRecipientRe.FindAllStringSubmatchIndex(s, -1) {
+		groupID, _ := strconv.Atoi(s[match[2]:match[3]])
+		recipients = append(recipients, Recipient{
+			Type:    "MediatorGroupRecipient",
+			GroupID: groupID,
+		})
 	}
 
 	return recipients
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -11,59 +12,66 @@ type testCase struct {
 	expected Line
 }
 
-func TestProcessLine(t *testing.T) {
-	lines := []testCase{
-		{
-			input: `2025-12-03T17:05:35.550490804Z {"@timestamp":"2025-12-03T17:05:35.550Z","message":"Computed following cost for submission request using topology at 2025-12-03T17:05:13.036775Z: EventCostDetails(\n  event cost = 7034,\n  cost multiplier = 4,\n  group to members size = MediatorGroupRecipient(group = 0) -> 14,\n  envelopes cost details = Seq(\n    EnvelopeCostDetails(write cost = 1017, read cost = 5, final cost = 1022, recipients = MediatorGroupRecipient(group = 0)),\n    EnvelopeCostDetails(\n      write cost = 146,\n      read cost = 1,\n      final cost = 147,\n      recipients = Seq(\n        MemberRecipient(PAR::Global-Synchronizer-Foundation::12203585ef82...),\n        MemberRecipient(PAR::Five-North-1::12206609cad5...),\n        MemberRecipient(PAR::Digital-Asset-2::1220e5a29d39...),\n        MemberRecipient(PAR::DA-Helm-Test-Node::1220d384538d...),\n        MediatorGroupRecipient(group = 0),\n        MemberRecipient(PAR::Proof-Group-1::12202637ebef...),\n        MemberRecipient(PAR::Tradeweb-Markets-1::122086f1bf3e...),\n        MemberRecipient(PAR::C7-Technology-Services-Limited::1220a9f22cd0...),\n        MemberRecipient(PAR::MPC-Holding-Inc::1220a4cf5243...),\n        MemberRecipient(PAR::Cumberland-1::122093af9243...),\n        MemberRecipient(PAR::SV-Nodeops-Limited::1220bfc8fc1c...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::Orb-1-LP-1::1220ad5f7aa9...),\n        MemberRecipient(PAR::Digital-Asset-1::12201929674c...),\n        MemberRecipient(PAR::Liberty-City-Ventures-1::12206232f0e1...),\n        MemberRecipient(PAR::Cumberland-2::1220706515eb...)\n      )\n    ),\n    EnvelopeCostDetails(\n      write cost = 5831,\n      read cost = 34,\n      final cost = 5865,\n      recipients = Seq(\n        MemberRecipient(PAR::Global-Synchronizer-Foundation::12203585ef82...),\n        MemberRecipient(PAR::Five-North-1::12206609cad5...),\n        MemberRecipient(PAR::Digital-Asset-2::1220e5a29d39...),\n        MemberRecipient(PAR::DA-Helm-Test-Node::1220d384538d...),\n        MemberRecipient(PAR::Proof-Group-1::12202637ebef...),\n        MemberRecipient(PAR::Tradeweb-Markets-1::122086f1bf3e...),\n        MemberRecipient(PAR::C7-Technology-Services-Limited::1220a9f22cd0...),\n        MemberRecipient(PAR::MPC-Holding-Inc::1220a4cf5243...),\n        MemberRecipient(PAR::Cumberland-1::122093af9243...),\n        MemberRecipient(PAR::SV-Nodeops-Limited::1220bfc8fc1c...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::Orb-1-LP-1::1220ad5f7aa9...),\n        MemberRecipient(PAR::Digital-Asset-1::12201929674c...),\n        MemberRecipient(PAR::Liberty-City-Ventures-1::12206232f0e1...),\n        MemberRecipient(PAR::Cumberland-2::1220706515eb...)\n      )\n    )\n  )\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-2557","level":"DEBUG","span-id":"88a5ea7ae5453bf8","span-parent-id":"43da2fb007bd64b9","trace-id":"1361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.sendAsync"}`,
-			expected: Line{
-				SpanID:       "88a5ea7ae5453bf8",
-				SpanParentID: "43da2fb007bd64b9",
-				TraceID:      "1361e791b2456d77f309041540e6bc5a",
-				CostDetails: &EventCostDetails{
-					EventCost:      7034,
-					CostMultiplier: 4,
-				},
+var eventCostCases = []testCase{
+	{
+		input: `2025-12-03T17:05:35.550490804Z {"@timestamp":"2025-12-03T17:05:35.550Z","message":"Computed following cost for submission request using topology at 2025-12-03T17:05:13.036775Z: EventCostDetails(\n  event cost = 7034,\n  cost multiplier = 4,\n  group to members size = MediatorGroupRecipient(group = 0) -> 14,\n  envelopes cost details = Seq(\n    EnvelopeCostDetails(write cost = 1017, read cost = 5, final cost = 1022, recipients = MediatorGroupRecipient(group = 0)),\n    EnvelopeCostDetails(\n      write cost = 146,\n      read cost = 1,\n      final cost = 147,\n      recipients = Seq(\n        MemberRecipient(PAR::Global-Synchronizer-Foundation::12203585ef82...),\n        MemberRecipient(PAR::Five-North-1::12206609cad5...),\n        MemberRecipient(PAR::Digital-Asset-2::1220e5a29d39...),\n        MemberRecipient(PAR::DA-Helm-Test-Node::1220d384538d...),\n        MediatorGroupRecipient(group = 0),\n        MemberRecipient(PAR::Proof-Group-1::12202637ebef...),\n        MemberRecipient(PAR::Tradeweb-Markets-1::122086f1bf3e...),\n        MemberRecipient(PAR::C7-Technology-Services-Limited::1220a9f22cd0...),\n        MemberRecipient(PAR::MPC-Holding-Inc::1220a4cf5243...),\n        MemberRecipient(PAR::Cumberland-1::122093af9243...),\n        MemberRecipient(PAR::SV-Nodeops-Limited::1220bfc8fc1c...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::Orb-1-LP-1::1220ad5f7aa9...),\n        MemberRecipient(PAR::Digital-Asset-1::12201929674c...),\n        MemberRecipient(PAR::Liberty-City-Ventures-1::12206232f0e1...),\n        MemberRecipient(PAR::Cumberland-2::1220706515eb...)\n      )\n    ),\n    EnvelopeCostDetails(\n      write cost = 5831,\n      read cost = 34,\n      final cost = 5865,\n      recipients = Seq(\n        MemberRecipient(PAR::Global-Synchronizer-Foundation::12203585ef82...),\n        MemberRecipient(PAR::Five-North-1::12206609cad5...),\n        MemberRecipient(PAR::Digital-Asset-2::1220e5a29d39...),\n        MemberRecipient(PAR::DA-Helm-Test-Node::1220d384538d...),\n        MemberRecipient(PAR::Proof-Group-1::12202637ebef...),\n        MemberRecipient(PAR::Tradeweb-Markets-1::122086f1bf3e...),\n        MemberRecipient(PAR::C7-Technology-Services-Limited::1220a9f22cd0...),\n        MemberRecipient(PAR::MPC-Holding-Inc::1220a4cf5243...),\n        MemberRecipient(PAR::Cumberland-1::122093af9243...),\n        MemberRecipient(PAR::SV-Nodeops-Limited::1220bfc8fc1c...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::Orb-1-LP-1::1220ad5f7aa9...),\n        MemberRecipient(PAR::Digital-Asset-1::12201929674c...),\n        MemberRecipient(PAR::Liberty-City-Ventures-1::12206232f0e1...),\n        MemberRecipient(PAR::Cumberland-2::1220706515eb...)\n      )\n    )\n  )\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-2557","level":"DEBUG","span-id":"88a5ea7ae5453bf8","span-parent-id":"43da2fb007bd64b9","trace-id":"1361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.sendAsync"}`,
+		expected: Line{
+			SpanID:       "88a5ea7ae5453bf8",
+			SpanParentID: "43da2fb007bd64b9",
+			TraceID:      "1361e791b2456d77f309041540e6bc5a",
+			CostDetails: &EventCostDetails{
+				EventCost:      7034,
+				CostMultiplier: 4,
 			},
 		},
-		{
-			input: `2025-12-03T17:05:36.312659459Z {"@timestamp":"2025-12-03T17:05:36.310Z","message":"Computed following cost for submission request using topology at 2025-12-03T17:05:35.696292Z: EventCostDetails(\n  event cost = 343,\n  cost multiplier = 4,\n  group to members size = MediatorGroupRecipient(group = 0) -> 14,\n  envelopes cost details = EnvelopeCostDetails(write cost = 342, read cost = 1, final cost = 343, recipients = MediatorGroupRecipient(group = 0))\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1272","level":"DEBUG","span-id":"b891c2f180fd65e3","span-parent-id":"44699b96955349b4","trace-id":"1361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.sendAsync"}`,
-			expected: Line{
-				SpanID:       "b891c2f180fd65e3",
-				SpanParentID: "44699b96955349b4",
-				TraceID:      "1361e791b2456d77f309041540e6bc5a",
-				CostDetails: &EventCostDetails{
-					EventCost:      343,
-					CostMultiplier: 4,
-				},
+	},
+	{
+		input: `2025-12-03T17:05:36.312659459Z {"@timestamp":"2025-12-03T17:05:36.310Z","message":"Computed following cost for submission request using topology at 2025-12-03T17:05:35.696292Z: EventCostDetails(\n  event cost = 343,\n  cost multiplier = 4,\n  group to members size = MediatorGroupRecipient(group = 0) -> 14,\n  envelopes cost details = EnvelopeCostDetails(write cost = 342, read cost = 1, final cost = 343, recipients = MediatorGroupRecipient(group = 0))\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1272","level":"DEBUG","span-id":"b891c2f180fd65e3","span-parent-id":"44699b96955349b4","trace-id":"1361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.sendAsync"}`,
+		expected: Line{
+			SpanID:       "b891c2f180fd65e3",
+			SpanParentID: "44699b96955349b4",
+			TraceID:      "1361e791b2456d77f309041540e6bc5a",
+			CostDetails: &EventCostDetails{
+				EventCost:      343,
+				CostMultiplier: 4,
 			},
 		},
-		{
-			input: `2025-12-03T17:05:47.049651881Z {"@timestamp":"2025-12-03T17:05:47.049Z","message":"Computed following cost for submission request using topology at 2025-12-03T17:05:36.964037Z: EventCostDetails(\n  event cost = 12491,\n  cost multiplier = 4,\n  group to members size = MediatorGroupRecipient(group = 0) -> 14,\n  envelopes cost details = Seq(\n    EnvelopeCostDetails(write cost = 1225, read cost = 6, final cost = 1231, recipients = MediatorGroupRecipient(group = 0)),\n    EnvelopeCostDetails(\n      write cost = 146,\n      read cost = 1,\n      final cost = 147,\n      recipients = Seq(\n        MemberRecipient(PAR::Global-Synchronizer-Foundation::12203585ef82...),\n        MemberRecipient(PAR::Five-North-1::12206609cad5...),\n        MemberRecipient(PAR::Digital-Asset-2::1220e5a29d39...),\n        MemberRecipient(PAR::DA-Helm-Test-Node::1220d384538d...),\n        MediatorGroupRecipient(group = 0),\n        MemberRecipient(PAR::Proof-Group-1::12202637ebef...),\n        MemberRecipient(PAR::Tradeweb-Markets-1::122086f1bf3e...),\n        MemberRecipient(PAR::C7-Technology-Services-Limited::1220a9f22cd0...),\n        MemberRecipient(PAR::MPC-Holding-Inc::1220a4cf5243...),\n        MemberRecipient(PAR::Cumberland-1::122093af9243...),\n        MemberRecipient(PAR::SV-Nodeops-Limited::1220bfc8fc1c...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::Orb-1-LP-1::1220ad5f7aa9...),\n        MemberRecipient(PAR::Digital-Asset-1::12201929674c...),\n        MemberRecipient(PAR::Liberty-City-Ventures-1::12206232f0e1...),\n        MemberRecipient(PAR::Cumberland-2::1220706515eb...)\n      )\n    ),\n    EnvelopeCostDetails(write cost = 3169, read cost = 1, final cost = 3170, recipients = MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...)),\n    EnvelopeCostDetails(\n      write cost = 7896,\n      read cost = 47,\n      final cost = 7943,\n      recipients = Seq(\n        MemberRecipient(PAR::Global-Synchronizer-Foundation::12203585ef82...),\n        MemberRecipient(PAR::Five-North-1::12206609cad5...),\n        MemberRecipient(PAR::Digital-Asset-2::1220e5a29d39...),\n        MemberRecipient(PAR::DA-Helm-Test-Node::1220d384538d...),\n        MemberRecipient(PAR::Proof-Group-1::12202637ebef...),\n        MemberRecipient(PAR::Tradeweb-Markets-1::122086f1bf3e...),\n        MemberRecipient(PAR::C7-Technology-Services-Limited::1220a9f22cd0...),\n        MemberRecipient(PAR::MPC-Holding-Inc::1220a4cf5243...),\n        MemberRecipient(PAR::Cumberland-1::122093af9243...),\n        MemberRecipient(PAR::SV-Nodeops-Limited::1220bfc8fc1c...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::Orb-1-LP-1::1220ad5f7aa9...),\n        MemberRecipient(PAR::Digital-Asset-1::12201929674c...),\n        MemberRecipient(PAR::Liberty-City-Ventures-1::12206232f0e1...),\n        MemberRecipient(PAR::Cumberland-2::1220706515eb...)\n      )\n    )\n  )\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-4829","level":"DEBUG","span-id":"fdb6ff808140463f","span-parent-id":"a82c85131b684394","trace-id":"ce6e1267e72dd2c1411c4bdda0025613","span-name":"SequencerClient.sendAsync"}`,
-			expected: Line{
-				SpanID:       "fdb6ff808140463f",
-				SpanParentID: "a82c85131b684394",
-				TraceID:      "ce6e1267e72dd2c1411c4bdda0025613",
-				CostDetails: &EventCostDetails{
-					EventCost:      12491,
-					CostMultiplier: 4,
-				},
+	},
+	{
+		input: `2025-12-03T17:05:47.049651881Z {"@timestamp":"2025-12-03T17:05:47.049Z","message":"Computed following cost for submission request using topology at 2025-12-03T17:05:36.964037Z: EventCostDetails(\n  event cost = 12491,\n  cost multiplier = 4,\n  group to members size = MediatorGroupRecipient(group = 0) -> 14,\n  envelopes cost details = Seq(\n    EnvelopeCostDetails(write cost = 1225, read cost = 6, final cost = 1231, recipients = MediatorGroupRecipient(group = 0)),\n    EnvelopeCostDetails(\n      write cost = 146,\n      read cost = 1,\n      final cost = 147,\n      recipients = Seq(\n        MemberRecipient(PAR::Global-Synchronizer-Foundation::12203585ef82...),\n        MemberRecipient(PAR::Five-North-1::12206609cad5...),\n        MemberRecipient(PAR::Digital-Asset-2::1220e5a29d39...),\n        MemberRecipient(PAR::DA-Helm-Test-Node::1220d384538d...),\n        MediatorGroupRecipient(group = 0),\n        MemberRecipient(PAR::Proof-Group-1::12202637ebef...),\n        MemberRecipient(PAR::Tradeweb-Markets-1::122086f1bf3e...),\n        MemberRecipient(PAR::C7-Technology-Services-Limited::1220a9f22cd0...),\n        MemberRecipient(PAR::MPC-Holding-Inc::1220a4cf5243...),\n        MemberRecipient(PAR::Cumberland-1::122093af9243...),\n        MemberRecipient(PAR::SV-Nodeops-Limited::1220bfc8fc1c...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::Orb-1-LP-1::1220ad5f7aa9...),\n        MemberRecipient(PAR::Digital-Asset-1::12201929674c...),\n        MemberRecipient(PAR::Liberty-City-Ventures-1::12206232f0e1...),\n        MemberRecipient(PAR::Cumberland-2::1220706515eb...)\n      )\n    ),\n    EnvelopeCostDetails(write cost = 3169, read cost = 1, final cost = 3170, recipients = MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...)),\n    EnvelopeCostDetails(\n      write cost = 7896,\n      read cost = 47,\n      final cost = 7943,\n      recipients = Seq(\n        MemberRecipient(PAR::Global-Synchronizer-Foundation::12203585ef82...),\n        MemberRecipient(PAR::Five-North-1::12206609cad5...),\n        MemberRecipient(PAR::Digital-Asset-2::1220e5a29d39...),\n        MemberRecipient(PAR::DA-Helm-Test-Node::1220d384538d...),\n        MemberRecipient(PAR::Proof-Group-1::12202637ebef...),\n        MemberRecipient(PAR::Tradeweb-Markets-1::122086f1bf3e...),\n        MemberRecipient(PAR::C7-Technology-Services-Limited::1220a9f22cd0...),\n        MemberRecipient(PAR::MPC-Holding-Inc::1220a4cf5243...),\n        MemberRecipient(PAR::Cumberland-1::122093af9243...),\n        MemberRecipient(PAR::SV-Nodeops-Limited::1220bfc8fc1c...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::Orb-1-LP-1::1220ad5f7aa9...),\n        MemberRecipient(PAR::Digital-Asset-1::12201929674c...),\n        MemberRecipient(PAR::Liberty-City-Ventures-1::12206232f0e1...),\n        MemberRecipient(PAR::Cumberland-2::1220706515eb...)\n      )\n    )\n  )\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-4829","level":"DEBUG","span-id":"fdb6ff808140463f","span-parent-id":"a82c85131b684394","trace-id":"ce6e1267e72dd2c1411c4bdda0025613","span-name":"SequencerClient.sendAsync"}`,
+		expected: Line{
+			SpanID:       "fdb6ff808140463f",
+			SpanParentID: "a82c85131b684394",
+			TraceID:      "ce6e1267e72dd2c1411c4bdda0025613",
+			CostDetails: &EventCostDetails{
+				EventCost:      12491,
+				CostMultiplier: 4,
 			},
 		},
-		{
-			input: `2025-12-03T17:06:09.045028988Z {"@timestamp":"2025-12-03T17:06:09.044Z","message":"Computed following cost for submission request using topology at 2025-12-03T17:05:48.668460Z: EventCostDetails(\n  event cost = 14097,\n  cost multiplier = 4,\n  group to members size = MediatorGroupRecipient(group = 0) -> 14,\n  envelopes cost details = Seq(\n    EnvelopeCostDetails(write cost = 2483, read cost = 13, final cost = 2496, recipients = MediatorGroupRecipient(group = 0)),\n    EnvelopeCostDetails(\n      write cost = 146,\n      read cost = 1,\n      final cost = 147,\n      recipients = Seq(\n        MemberRecipient(PAR::iBTC-validator-3::1220d544125d...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::fivenorth-devnet-1::12208c929c3f...),\n        MemberRecipient(PAR::iBTC-validator-2::122099953934...),\n        MediatorGroupRecipient(group = 0),\n        MemberRecipient(PAR::digitalasset-utility::1220d2d732d0...)\n      )\n    ),\n    EnvelopeCostDetails(\n      write cost = 3468,\n      read cost = 4,\n      final cost = 3472,\n      recipients = Seq(MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...), MemberRecipient(PAR::iBTC-validator-2::122099953934...), MemberRecipient(PAR::iBTC-validator-3::1220d544125d...))\n    ),\n    EnvelopeCostDetails(\n      write cost = 2440,\n      read cost = 3,\n      final cost = 2443,\n      recipients = Seq(\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::iBTC-validator-2::122099953934...),\n        MemberRecipient(PAR::iBTC-validator-3::1220d544125d...),\n        MemberRecipient(PAR::digitalasset-utility::1220d2d732d0...)\n      )\n    ),\n    EnvelopeCostDetails(\n      write cost = 2741,\n      read cost = 5,\n      final cost = 2746,\n      recipients = Seq(\n        MemberRecipient(PAR::iBTC-validator-3::1220d544125d...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::fivenorth-devnet-1::12208c929c3f...),\n        MemberRecipient(PAR::iBTC-validator-2::122099953934...),\n        MemberRecipient(PAR::digitalasset-utility::1220d2d732d0...)\n      )\n    ),\n    EnvelopeCostDetails(\n      write cost = 2788,\n      read cost = 5,\n      final cost = 2793,\n      recipients = Seq(\n        MemberRecipient(PAR::iBTC-validator-3::1220d544125d...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::fivenorth-devnet-1::12208c929c3f...),\n        MemberRecipient(PAR::iBTC-validator-2::122099953934...),\n        MemberRecipient(PAR::digitalasset-utility::1220d2d732d0...)\n      )\n    )\n  )\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1840","level":"DEBUG","span-id":"38a01e31b05296ed","span-parent-id":"9ab6e7e46d7807a7","trace-id":"8400687f8dbbef675fb7b6e4661f461d","span-name":"SequencerClient.sendAsync"}`,
-			expected: Line{
-				SpanID:       "38a01e31b05296ed",
-				SpanParentID: "9ab6e7e46d7807a7",
-				TraceID:      "8400687f8dbbef675fb7b6e4661f461d",
-				CostDetails: &EventCostDetails{
-					EventCost:      14097,
-					CostMultiplier: 4,
-				},
+	},
+	{
+		input: `2025-12-03T17:06:09.045028988Z {"@timestamp":"2025-12-03T17:06:09.044Z","message":"Computed following cost for submission request using topology at 2025-12-03T17:05:48.668460Z: EventCostDetails(\n  event cost = 14097,\n  cost multiplier = 4,\n  group to members size = MediatorGroupRecipient(group = 0) -> 14,\n  envelopes cost details = Seq(\n    EnvelopeCostDetails(write cost = 2483, read cost = 13, final cost = 2496, recipients = MediatorGroupRecipient(group = 0)),\n    EnvelopeCostDetails(\n      write cost = 146,\n      read cost = 1,\n      final cost = 147,\n      recipients = Seq(\n        MemberRecipient(PAR::iBTC-validator-3::1220d544125d...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::fivenorth-devnet-1::12208c929c3f...),\n        MemberRecipient(PAR::iBTC-validator-2::122099953934...),\n        MediatorGroupRecipient(group = 0),\n        MemberRecipient(PAR::digitalasset-utility::1220d2d732d0...)\n      )\n    ),\n    EnvelopeCostDetails(\n      write cost = 3468,\n      read cost = 4,\n      final cost = 3472,\n      recipients = Seq(MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...), MemberRecipient(PAR::iBTC-validator-2::122099953934...), MemberRecipient(PAR::iBTC-validator-3::1220d544125d...))\n    ),\n    EnvelopeCostDetails(\n      write cost = 2440,\n      read cost = 3,\n      final cost = 2443,\n      recipients = Seq(\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::iBTC-validator-2::122099953934...),\n        MemberRecipient(PAR::iBTC-validator-3::1220d544125d...),\n        MemberRecipient(PAR::digitalasset-utility::1220d2d732d0...)\n      )\n    ),\n    EnvelopeCostDetails(\n      write cost = 2741,\n      read cost = 5,\n      final cost = 2746,\n      recipients = Seq(\n        MemberRecipient(PAR::iBTC-validator-3::1220d544125d...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::fivenorth-devnet-1::12208c929c3f...),\n        MemberRecipient(PAR::iBTC-validator-2::122099953934...),\n        MemberRecipient(PAR::digitalasset-utility::1220d2d732d0...)\n      )\n    ),\n    EnvelopeCostDetails(\n      write cost = 2788,\n      read cost = 5,\n      final cost = 2793,\n      recipients = Seq(\n        MemberRecipient(PAR::iBTC-validator-3::1220d544125d...),\n        MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...),\n        MemberRecipient(PAR::fivenorth-devnet-1::12208c929c3f...),\n        MemberRecipient(PAR::iBTC-validator-2::122099953934...),\n        MemberRecipient(PAR::digitalasset-utility::1220d2d732d0...)\n      )\n    )\n  )\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1840","level":"DEBUG","span-id":"38a01e31b05296ed","span-parent-id":"9ab6e7e46d7807a7","trace-id":"8400687f8dbbef675fb7b6e4661f461d","span-name":"SequencerClient.sendAsync"}`,
+		expected: Line{
+			SpanID:       "38a01e31b05296ed",
+			SpanParentID: "9ab6e7e46d7807a7",
+			TraceID:      "8400687f8dbbef675fb7b6e4661f461d",
+			CostDetails: &EventCostDetails{
+				EventCost:      14097,
+				CostMultiplier: 4,
 			},
 		},
-	}
+	},
+}
 
-	for _, line := range lines {
+var (
+	stateLine     = `2025-12-03T17:05:36.312659459Z {"@timestamp":"2025-12-03T17:05:36.310Z","message":"Updating traffic state to TrafficState(\n  extraTrafficLimit = 1000000,\n  extraTrafficConsumed = 23456,\n  baseTrafficRemainder = 20000,\n  lastConsumedCost = 343,\n  timestamp = 2025-12-03T17:05:35.696292Z,\n  serial = 2\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1272","level":"DEBUG","span-id":"b891c2f180fd65e3","span-parent-id":"44699b96955349b4","trace-id":"1361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.sendAsync"}`
+	receiptLine   = `2025-12-03T17:05:37.112659459Z {"@timestamp":"2025-12-03T17:05:37.110Z","message":"Updating traffic state with receipt TrafficReceipt(consumedCost = 343, extraTrafficConsumed = 23799, baseTrafficRemainder = 20000)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1273","level":"DEBUG","span-id":"c891c2f180fd65e3","span-parent-id":"54699b96955349b4","trace-id":"1361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.handleEvent"}`
+	purchasedLine = `2025-12-03T17:10:00.000000000Z {"@timestamp":"2025-12-03T17:10:00.000Z","message":"Received new traffic purchased entry TrafficPurchased(member = PAR::iBTC-validator-1::1220fa8543db..., serial = 3, extraTrafficPurchased = 2000000, sequencingTimestamp = 2025-12-03T17:09:58.123456Z)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1274","level":"DEBUG","span-id":"d891c2f180fd65e3","span-parent-id":"64699b96955349b4","trace-id":"2361e791b2456d77f309041540e6bc5a","span-name":"TrafficPurchasedSubmissionHandler"}`
+	rejectionLine = `2025-12-03T17:05:36.312659459Z {"@timestamp":"2025-12-03T17:05:36.310Z","message":"Failed to send submission request: TRAFFIC_CONTROL_EXCEEDED(2,1361e791): AboveTrafficLimit(member = PAR::iBTC-validator-1::1220fa8543db..., trafficCost = 7034, remaining = 100)","logger_name":"c.d.c.s.c.SequencerClientImplPekko:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1272","level":"WARN","span-id":"b891c2f180fd65e3","span-parent-id":"44699b96955349b4","trace-id":"1361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.sendAsync"}`
+)
+
+func TestProcessLine(t *testing.T) {
+	for _, line := range eventCostCases {
 		l, err := ProcessLine(line.input)
 		if err != nil {
 			t.Errorf("Failed to process line: %v", err)
@@ -77,6 +85,15 @@ func TestProcessLine(t *testing.T) {
 		if l.TraceID != line.expected.TraceID {
 			t.Errorf("TraceID mismatch: got %s, want %s", l.TraceID, line.expected.TraceID)
 		}
+		if l.Participant != "participant" {
+			t.Errorf("Participant mismatch: got %s, want %s", l.Participant, "participant")
+		}
+		if l.Synchronizer != "global-domain::1220be58c29e" {
+			t.Errorf("Synchronizer mismatch: got %s, want %s", l.Synchronizer, "global-domain::1220be58c29e")
+		}
+		if problems := l.CostDetails.Validate(); len(problems) > 0 {
+			t.Errorf("Unexpected validation errors: %v", problems)
+		}
 		if l.CostDetails.EventCost != line.expected.CostDetails.EventCost {
 			t.Errorf("EventCost mismatch: got %d, want %d", l.CostDetails.EventCost, line.expected.CostDetails.EventCost)
 		}
@@ -87,3 +104,238 @@ func TestProcessLine(t *testing.T) {
 		fmt.Println(string(d))
 	}
 }
+
+func TestProcessLineTrafficEvents(t *testing.T) {
+	for _, input := range []string{stateLine, receiptLine, purchasedLine} {
+		if !Relevant([]byte(input)) {
+			t.Errorf("Line should be relevant: %s", input)
+		}
+	}
+
+	l, err := ProcessLine(stateLine)
+	if err != nil {
+		t.Fatalf("Failed to process line: %v", err)
+	}
+	if l.Kind != KindTrafficState {
+		t.Errorf("Kind mismatch: got %s, want %s", l.Kind, KindTrafficState)
+	}
+	if l.TrafficState == nil {
+		t.Fatalf("TrafficState should be parsed")
+	}
+	if l.TrafficState.ExtraTrafficPurchased != 1000000 {
+		t.Errorf("ExtraTrafficPurchased mismatch: got %d, want %d", l.TrafficState.ExtraTrafficPurchased, 1000000)
+	}
+	if l.TrafficState.ExtraTrafficConsumed != 23456 {
+		t.Errorf("ExtraTrafficConsumed mismatch: got %d, want %d", l.TrafficState.ExtraTrafficConsumed, 23456)
+	}
+	if l.TrafficState.AvailableTraffic() != 1000000-23456+20000 {
+		t.Errorf("AvailableTraffic mismatch: got %d, want %d", l.TrafficState.AvailableTraffic(), 1000000-23456+20000)
+	}
+	if l.TrafficState.Serial != 2 {
+		t.Errorf("Serial mismatch: got %d, want %d", l.TrafficState.Serial, 2)
+	}
+	if l.TrafficState.Timestamp.IsZero() {
+		t.Errorf("Timestamp should be parsed")
+	}
+
+	l, err = ProcessLine(receiptLine)
+	if err != nil {
+		t.Fatalf("Failed to process line: %v", err)
+	}
+	if l.Kind != KindTrafficReceipt {
+		t.Errorf("Kind mismatch: got %s, want %s", l.Kind, KindTrafficReceipt)
+	}
+	if l.TrafficReceipt == nil || l.TrafficReceipt.ConsumedCost != 343 || l.TrafficReceipt.ExtraTrafficConsumed != 23799 {
+		t.Errorf("TrafficReceipt mismatch: got %+v", l.TrafficReceipt)
+	}
+
+	l, err = ProcessLine(purchasedLine)
+	if err != nil {
+		t.Fatalf("Failed to process line: %v", err)
+	}
+	if l.Kind != KindTrafficPurchased {
+		t.Errorf("Kind mismatch: got %s, want %s", l.Kind, KindTrafficPurchased)
+	}
+	if l.TrafficPurchased == nil {
+		t.Fatalf("TrafficPurchased should be parsed")
+	}
+	if l.TrafficPurchased.Member != "PAR::iBTC-validator-1::1220fa8543db..." {
+		t.Errorf("Member mismatch: got %s", l.TrafficPurchased.Member)
+	}
+	if l.TrafficPurchased.ExtraTrafficPurchased != 2000000 || l.TrafficPurchased.Serial != 3 {
+		t.Errorf("TrafficPurchased mismatch: got %+v", l.TrafficPurchased)
+	}
+	if l.TrafficPurchased.SequencingTimestamp.IsZero() {
+		t.Errorf("SequencingTimestamp should be parsed")
+	}
+}
+
+func TestProcessLineTrafficRejection(t *testing.T) {
+	lines := []struct {
+		input     string
+		code      string
+		required  int
+		available int
+	}{
+		{
+			input:     rejectionLine,
+			code:      "TRAFFIC_CONTROL_EXCEEDED",
+			required:  7034,
+			available: 100,
+		},
+		{
+			input:     `2025-12-03T17:05:36.312659459Z {"@timestamp":"2025-12-03T17:05:36.310Z","message":"Submission rejected due to insufficient traffic: EventCostDetails(\n  event cost = 343,\n  cost multiplier = 4\n), TrafficState(extraTrafficLimit = 1000, extraTrafficConsumed = 1000, baseTrafficRemainder = 20, lastConsumedCost = 10, timestamp = 2025-12-03T17:05:35.696292Z)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1272","level":"WARN","span-id":"b891c2f180fd65e3","span-parent-id":"44699b96955349b4","trace-id":"2361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.sendAsync"}`,
+			code:      "INSUFFICIENT_TRAFFIC",
+			required:  343,
+			available: 20,
+		},
+		{
+			input:     `2025-12-03T17:05:36.312659459Z {"@timestamp":"2025-12-03T17:05:36.310Z","message":"Submission rejected due to insufficient traffic (retries remaining: 2, required: 1): EventCostDetails(\n  event cost = 343,\n  cost multiplier = 4\n), TrafficState(extraTrafficLimit = 1000, extraTrafficConsumed = 1000, baseTrafficRemainder = 20, lastConsumedCost = 10, timestamp = 2025-12-03T17:05:35.696292Z)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1272","level":"WARN","span-id":"b891c2f180fd65e3","span-parent-id":"44699b96955349b4","trace-id":"2361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.sendAsync"}`,
+			code:      "INSUFFICIENT_TRAFFIC",
+			required:  343,
+			available: 20,
+		},
+	}
+
+	for _, line := range lines {
+		if !Relevant([]byte(line.input)) {
+			t.Errorf("Line should be relevant: %s", line.input)
+		}
+		l, err := ProcessLine(line.input)
+		if err != nil {
+			t.Fatalf("Failed to process line: %v", err)
+		}
+		if l.Kind != KindTrafficRejection {
+			t.Errorf("Kind mismatch: got %s, want %s", l.Kind, KindTrafficRejection)
+		}
+		if l.TrafficRejection == nil {
+			t.Fatalf("TrafficRejection should be parsed")
+		}
+		if l.TrafficRejection.Code != line.code {
+			t.Errorf("Code mismatch: got %s, want %s", l.TrafficRejection.Code, line.code)
+		}
+		if l.TrafficRejection.Required != line.required {
+			t.Errorf("Required mismatch: got %d, want %d", l.TrafficRejection.Required, line.required)
+		}
+		if l.TrafficRejection.Available != line.available {
+			t.Errorf("Available mismatch: got %d, want %d", l.TrafficRejection.Available, line.available)
+		}
+		if l.TraceID == "" {
+			t.Errorf("TraceID should be parsed")
+		}
+	}
+}
+
+func TestValidateCostDetails(t *testing.T) {
+	// final cost of the second envelope is off by one, so neither the envelope nor the event cost adds up
+	input := `2025-12-03T17:05:36.312659459Z {"@timestamp":"2025-12-03T17:05:36.310Z","message":"Computed following cost for submission request using topology at 2025-12-03T17:05:35.696292Z: EventCostDetails(\n  event cost = 500,\n  cost multiplier = 4,\n  group to members size = MediatorGroupRecipient(group = 0) -> 14,\n  envelopes cost details = Seq(\n    EnvelopeCostDetails(write cost = 342, read cost = 1, final cost = 343, recipients = MediatorGroupRecipient(group = 0)),\n    EnvelopeCostDetails(write cost = 150, read cost = 1, final cost = 152, recipients = MemberRecipient(PAR::iBTC-validator-1::1220fa8543db...))\n  )\n)","logger_name":"c.d.c.s.t.TrafficStateController:participant=participant/psid=IndexedPhysicalSynchronizer(global-domain::1220be58c29e::34-0,2)","thread_name":"canton-env-ec-1272","level":"DEBUG","span-id":"b891c2f180fd65e3","span-parent-id":"44699b96955349b4","trace-id":"1361e791b2456d77f309041540e6bc5a","span-name":"SequencerClient.sendAsync"}`
+
+	defer Configure(options)
+	Configure(Options{ValidationMode: ValidationTag})
+	l, err := ProcessLine(input)
+	if err != nil {
+		t.Fatalf("Failed to process line: %v", err)
+	}
+	if len(l.CostDetails.ValidationErrors) != 2 {
+		t.Errorf("ValidationErrors mismatch: got %v, want 2 errors", l.CostDetails.ValidationErrors)
+	}
+
+	Configure(Options{ValidationMode: ValidationDrop})
+	if _, err := ProcessLine(input); !errors.Is(err, ErrInconsistentCostDetails) {
+		t.Errorf("Error mismatch: got %v, want %v", err, ErrInconsistentCostDetails)
+	}
+
+	Configure(Options{ValidationMode: ValidationWarn})
+	l, err = ProcessLine(input)
+	if err != nil {
+		t.Fatalf("Failed to process line: %v", err)
+	}
+	if len(l.CostDetails.ValidationErrors) != 0 {
+		t.Errorf("ValidationErrors should be empty in warn mode, got %v", l.CostDetails.ValidationErrors)
+	}
+}
+
+func TestRelevant(t *testing.T) {
+	if Relevant([]byte(noiseLine)) {
+		t.Errorf("Noise line should not be relevant")
+	}
+	if !Relevant([]byte(`2025-12-03T17:05:35.550490804Z {"message":"EVENTCOST in upper case"}`)) {
+		t.Errorf("Markers should be matched case-insensitively")
+	}
+	if Relevant([]byte(`2025-12-03T17:05:35.550490804Z {"logger_name":"c.d.c.s.t.TrafficStateController"}`)) {
+		t.Errorf("Logger name should not be relevant")
+	}
+}
+
+func TestParseEnvelopeCostDetails(t *testing.T) {
+	l, err := ProcessLine(eventCostCases[3].input)
+	if err != nil {
+		t.Fatalf("Failed to process line: %v", err)
+	}
+
+	recipients := []int{1, 6, 3, 4, 5, 5}
+	if len(l.CostDetails.EnvelopesCost) != len(recipients) {
+		t.Fatalf("Envelope count mismatch: got %d, want %d", len(l.CostDetails.EnvelopesCost), len(recipients))
+	}
+	for i, envelope := range l.CostDetails.EnvelopesCost {
+		if len(envelope.Recipients) != recipients[i] {
+			t.Errorf("Envelope %d recipient count mismatch: got %d, want %d", i, len(envelope.Recipients), recipients[i])
+		}
+	}
+	if l.CostDetails.GroupToMembersSize[0] != 14 {
+		t.Errorf("GroupToMembersSize mismatch: got %v", l.CostDetails.GroupToMembersSize)
+	}
+
+	member := l.CostDetails.EnvelopesCost[2].Recipients[0]
+	if member.Type != "MemberRecipient" || member.Member != "PAR::iBTC-validator-1::1220fa8543db..." {
+		t.Errorf("Recipient mismatch: got %+v", member)
+	}
+	mediator := l.CostDetails.EnvelopesCost[0].Recipients[0]
+	if mediator.Type != "MediatorGroupRecipient" || mediator.GroupID != 0 {
+		t.Errorf("Recipient mismatch: got %+v", mediator)
+	}
+}
+
+// noiseLine is a typical DEBUG line of a chatty participant which is not about traffic
+var noiseLine = `2025-12-03T17:05:36.412659459Z {"@timestamp":"2025-12-03T17:05:36.410Z","message":"Processing event at counter 1282734 with timestamp 2025-12-03T17:05:36.301228Z: Deliver(counter = 1282734, timestamp = 2025-12-03T17:05:36.301228Z, synchronizer id = global-domain::1220be58c29e, message id = 4e1a9c2f-5c1e-4a4b-9a3e-0f6b2c1d2e3f, batch = Batch(envelopes = Seq(ClosedEnvelope(recipients = MediatorGroupRecipient(group = 0), bytestring size = 1022))))","logger_name":"c.d.c.p.p.ParallelIndexerSubscription:participant=participant","thread_name":"canton-env-ec-1275","level":"DEBUG","span-id":"e891c2f180fd65e3","span-parent-id":"74699b96955349b4","trace-id":"3361e791b2456d77f309041540e6bc5a","span-name":"ApplicationHandler.handle"}`
+
+var benchmarkLines = []struct {
+	name string
+	line string
+}{
+	{"noise", noiseLine},
+	{"event_cost_single", eventCostCases[1].input},
+	{"event_cost_seq", eventCostCases[2].input},
+	{"traffic_state", stateLine},
+	{"traffic_receipt", receiptLine},
+	{"traffic_purchased", purchasedLine},
+	{"traffic_rejection", rejectionLine},
+}
+
+func BenchmarkRelevant(b *testing.B) {
+	for _, bc := range benchmarkLines {
+		b.Run(bc.name, func(b *testing.B) {
+			line := []byte(bc.line)
+			b.SetBytes(int64(len(line)))
+			b.ReportAllocs()
+			for b.Loop() {
+				Relevant(line)
+			}
+		})
+	}
+}
+
+func BenchmarkProcessLine(b *testing.B) {
+	for _, bc := range benchmarkLines[1:] {
+		b.Run(bc.name, func(b *testing.B) {
+			line := []byte(bc.line)
+			b.SetBytes(int64(len(line)))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := ProcessBytes(line); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package parser
+
+import "bytes"
+
+// letterFrequency lists the lowercase letters from the most to the least common
+// one in English text, which is close enough for the Canton log messages.
+const letterFrequency = "etaoinshrdlcumwfgypbvkjxqz"
+
+// marker is a lowercase substring which is searched case-insensitively in the
+// raw log lines without lowercasing (and so copying) them first. The search is
+// anchored on the rarest letter of the marker, so bytes.IndexByte skips most of
+// the line and EqualFold only runs on a few candidate windows.
+type marker struct {
+	text   []byte
+	anchor int
+}
+
+func newMarker(text string) marker {
+	m := marker{text: []byte(text)}
+	rarest := -1
+	for i, c := range m.text {
+		if rank := bytes.IndexByte([]byte(letterFrequency), c); rank > rarest {
+			rarest = rank
+			m.anchor = i
+		}
+	}
+	if rarest == -1 {
+		panic("parser: marker without letters: " + text)
+	}
+	return m
+}
+
+func (m marker) in(line []byte) bool {
+	anchor := m.text[m.anchor]
+	return m.scan(line, anchor) || m.scan(line, anchor-'a'+'A')
+}
+
+func (m marker) scan(line []byte, anchor byte) bool {
+	for offset := m.anchor; offset < len(line); {
+		i := bytes.IndexByte(line[offset:], anchor)
+		if i == -1 {
+			return false
+		}
+		start := offset + i - m.anchor
+		if start+len(m.text) > len(line) {
+			return false
+		}
+		if bytes.EqualFold(line[start:start+len(m.text)], m.text) {
+			return true
+		}
+		offset += i + 1
+	}
+	return false
+}
+
+var (
+	eventCostMarker = newMarker("eventcost")
+	// trafficMarker is contained by all the other markers, so it gates them
+	trafficMarker = newMarker("traffic")
+
+	// eventMarkers are the substrings of the log lines which carry cost and traffic balance events
+	eventMarkers = []marker{
+		eventCostMarker,
+		newMarker("trafficstate("),
+		newMarker("trafficreceipt("),
+		newMarker("trafficpurchased("),
+	}
+	rejectionMarkers = newMarkers(trafficRejectionMarkers)
+)
+
+func newMarkers(texts []string) []marker {
+	markers := make([]marker, len(texts))
+	for i, text := range texts {
+		markers[i] = newMarker(text)
+	}
+	return markers
+}
+
+// Relevant reports whether the raw log line contains any of the events the parser
+// understands. It does not allocate, so it is cheap enough to run on every line.
+func Relevant(line []byte) bool {
+	if eventCostMarker.in(line) {
+		return true
+	}
+	if !trafficMarker.in(line) {
+		return false
+	}
+	return anyMarkerIn(eventMarkers, line) || anyMarkerIn(rejectionMarkers, line)
+}
+
+func anyMarkerIn(markers []marker, line []byte) bool {
+	for _, m := range markers {
+		if m.in(line) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package parser
+
+import "time"
+
+// TraceSummary is the total cost of a ledger submission: the sum of all the
+// EventCost lines sharing the same trace id within the aggregation window.
+type TraceSummary struct {
+	TraceID            string    `json:"trace_id"`
+	EventCount         int       `json:"event_count"`
+	TotalCost          int       `json:"total_cost"`
+	EnvelopeCount      int       `json:"envelope_count"`
+	DistinctRecipients int       `json:"distinct_recipients"`
+	FirstTimestamp     time.Time `json:"first_timestamp"`
+	LastTimestamp      time.Time `json:"last_timestamp"`
+}
+
+// CounterpartyCost is the cost attributed to a recipient of the envelopes
+// within a time window, according to the attribution policy.
+type CounterpartyCost struct {
+	Counterparty   string    `json:"counterparty"`
+	Policy         string    `json:"policy"`
+	WindowStart    time.Time `json:"window_start"`
+	WindowEnd      time.Time `json:"window_end"`
+	AttributedCost float64   `json:"attributed_cost"`
+	EnvelopeCount  int       `json:"envelope_count"`
+}
+
+// Rollup is the statistics of the event costs within a tumbling time window,
+// per participant, synchronizer and span name.
+type Rollup struct {
+	Participant  string    `json:"participant"`
+	Synchronizer string    `json:"synchronizer"`
+	SpanName     string    `json:"span_name"`
+	Window       string    `json:"window"`
+	WindowStart  time.Time `json:"window_start"`
+	WindowEnd    time.Time `json:"window_end"`
+	Count        int       `json:"count"`
+	Sum          int       `json:"sum"`
+	Min          int       `json:"min"`
+	Max          int       `json:"max"`
+	P50          int       `json:"p50"`
+	P95          int       `json:"p95"`
+	P99          int       `json:"p99"`
+}
+
+// CostEstimate is the estimated price of the traffic of a record, according to
+// the rates effective at its timestamp.
+type CostEstimate struct {
+	Traffic            float64   `json:"traffic"`
+	USD                float64   `json:"usd"`
+	CC                 float64   `json:"cc"`
+	USDPerMB           float64   `json:"usd_per_mb"`
+	USDPerCC           float64   `json:"usd_per_cc"`
+	RatesEffectiveFrom time.Time `json:"rates_effective_from"`
+}
+
+// Anomaly is an outlier of a submission metric compared to the baseline of its key
+type Anomaly struct {
+	Key      string  `json:"key"`
+	Metric   string  `json:"metric"`
+	Method   string  `json:"method"`
+	Value    float64 `json:"value"`
+	Mean     float64 `json:"mean"`
+	StdDev   float64 `json:"std_dev"`
+	Score    float64 `json:"score"`
+	Baseline int     `json:"baseline_samples"`
+}
+
+// Submission is a Ledger API command submission reported by the application
+// which made it, joined with the cost events by trace id.
+type Submission struct {
+	TraceID       string `json:"trace_id"`
+	CommandID     string `json:"command_id"`
+	SubmissionID  string `json:"submission_id,omitempty"`
+	User          string `json:"user,omitempty"`
+	ApplicationID string `json:"application_id,omitempty"`
+	TemplateID    string `json:"template_id,omitempty"`
+	Choice        string `json:"choice,omitempty"`
+}
@@ -0,0 +1,204 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrafficState represents a snapshot of the member's traffic balance as logged
+// by the TrafficStateController, e.g.
+// TrafficState(extraTrafficLimit = 100000, extraTrafficConsumed = 5000, ...)
+type TrafficState struct {
+	ExtraTrafficPurchased int       `json:"extra_traffic_purchased"`
+	ExtraTrafficConsumed  int       `json:"extra_traffic_consumed"`
+	BaseTrafficRemainder  int       `json:"base_traffic_remainder"`
+	LastConsumedCost      int       `json:"last_consumed_cost"`
+	Timestamp             time.Time `json:"timestamp"`
+	Serial                int       `json:"serial,omitempty"`
+}
+
+// ExtraTrafficRemaining returns the purchased extra traffic which is not consumed yet.
+func (s *TrafficState) ExtraTrafficRemaining() int {
+	return s.ExtraTrafficPurchased - s.ExtraTrafficConsumed
+}
+
+// AvailableTraffic returns the traffic which can still be spent: the remaining
+// extra traffic plus the base rate remainder.
+func (s *TrafficState) AvailableTraffic() int {
+	return s.ExtraTrafficRemaining() + s.BaseTrafficRemainder
+}
+
+// TrafficReceipt represents the traffic receipt attached to a delivered event, e.g.
+// TrafficReceipt(consumedCost = 343, extraTrafficConsumed = 5000, baseTrafficRemainder = 20000)
+type TrafficReceipt struct {
+	ConsumedCost         int `json:"consumed_cost"`
+	ExtraTrafficConsumed int `json:"extra_traffic_consumed"`
+	BaseTrafficRemainder int `json:"base_traffic_remainder"`
+}
+
+// TrafficPurchased represents a top-up of the member's extra traffic, e.g.
+// TrafficPurchased(member = PAR::..., serial = 3, extraTrafficPurchased = 100000, sequencingTimestamp = ...)
+type TrafficPurchased struct {
+	Member                string    `json:"member"`
+	Serial                int       `json:"serial"`
+	ExtraTrafficPurchased int       `json:"extra_traffic_purchased"`
+	SequencingTimestamp   time.Time `json:"sequencing_timestamp"`
+}
+
+// Canton prints these parameters either in camelCase or space separated,
+// depending on the version, so every parameter has several accepted keys.
+var (
+	extraTrafficPurchasedKeys = []string{"extraTrafficPurchased = ", "extraTrafficLimit = ", "extra traffic purchased = ", "extra traffic limit = "}
+	extraTrafficConsumedKeys  = []string{"extraTrafficConsumed = ", "extra traffic consumed = "}
+	baseTrafficRemainderKeys  = []string{"baseTrafficRemainder = ", "base traffic remainder = "}
+	lastConsumedCostKeys      = []string{"lastConsumedCost = ", "last consumed cost = "}
+	consumedCostKeys          = []string{"consumedCost = ", "consumed cost = "}
+	trafficTimestampKeys      = []string{"sequencingTimestamp = ", "sequencing timestamp = ", "timestamp = "}
+	serialKeys                = []string{"serial = "}
+	memberKeys                = []string{"member = "}
+)
+
+func parseTrafficState(message string) *TrafficState {
+	section := extractSection(message, "TrafficState")
+	if section == "" {
+		return nil
+	}
+
+	return &TrafficState{
+		ExtraTrafficPurchased: intAfterAny(section, extraTrafficPurchasedKeys),
+		ExtraTrafficConsumed:  intAfterAny(section, extraTrafficConsumedKeys),
+		BaseTrafficRemainder:  intAfterAny(section, baseTrafficRemainderKeys),
+		LastConsumedCost:      intAfterAny(section, lastConsumedCostKeys),
+		Timestamp:             timeAfterAny(section, trafficTimestampKeys),
+		Serial:                intAfterAny(section, serialKeys),
+	}
+}
+
+func parseTrafficReceipt(message string) *TrafficReceipt {
+	section := extractSection(message, "TrafficReceipt")
+	if section == "" {
+		return nil
+	}
+
+	return &TrafficReceipt{
+		ConsumedCost:         intAfterAny(section, consumedCostKeys),
+		ExtraTrafficConsumed: intAfterAny(section, extraTrafficConsumedKeys),
+		BaseTrafficRemainder: intAfterAny(section, baseTrafficRemainderKeys),
+	}
+}
+
+func parseTrafficPurchased(message string) *TrafficPurchased {
+	section := extractSection(message, "TrafficPurchased")
+	if section == "" {
+		return nil
+	}
+
+	return &TrafficPurchased{
+		Member:                valueAfterAny(section, memberKeys),
+		Serial:                intAfterAny(section, serialKeys),
+		ExtraTrafficPurchased: intAfterAny(section, extraTrafficPurchasedKeys),
+		SequencingTimestamp:   timeAfterAny(section, trafficTimestampKeys),
+	}
+}
+
+// extractSection returns the content of the first name(...) occurrence in s,
+// or an empty string if it is not present.
+func extractSection(s string, name string) string {
+	idx := strings.Index(s, name+"(")
+	if idx == -1 {
+		return ""
+	}
+	return extractBalancedParens(s[idx+len(name):])
+}
+
+// valueAfterAny returns the value of the first key present in s, up to the next
+// separator, or an empty string
+func valueAfterAny(s string, keys []string) string {
+	for _, key := range keys {
+		idx := strings.Index(s, key)
+		if idx == -1 {
+			continue
+		}
+		value := s[idx+len(key):]
+		if end := strings.IndexAny(value, ",) \t\n"); end != -1 {
+			value = value[:end]
+		}
+		return value
+	}
+	return ""
+}
+
+func intAfterAny(s string, keys []string) int {
+	for _, key := range keys {
+		if strings.Contains(s, key) {
+			return intAfter(s, key)
+		}
+	}
+	return 0
+}
+
+func timeAfterAny(s string, keys []string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, valueAfterAny(s, keys))
+	return t
+}
+
+// TrafficRejection represents a submission which was refused because the member
+// ran out of traffic, e.g.
+// TRAFFIC_CONTROL_EXCEEDED(...): AboveTrafficLimit(member = PAR::..., trafficCost = 7034, remaining = 100)
+type TrafficRejection struct {
+	Code      string `json:"code"`
+	Member    string `json:"member,omitempty"`
+	Required  int    `json:"required"`
+	Available int    `json:"available"`
+}
+
+// trafficRejectionMarkers are the lowercase substrings of the log lines which
+// report a submission refused for insufficient traffic, see rejectionMarkers
+var trafficRejectionMarkers = []string{
+	"traffic_control_exceeded",
+	"insufficient traffic",
+	"not enough traffic",
+	"abovetrafficlimit",
+}
+
+// isTrafficRejection reports whether the raw log line reports a submission
+// refused for insufficient traffic
+func isTrafficRejection(line []byte) bool {
+	return anyMarkerIn(rejectionMarkers, line)
+}
+
+var (
+	rejectionCodeRe = regexp.MustCompile(`\b((?:[A-Z0-9]+_)*TRAFFIC_[A-Z0-9_]+)\b`)
+	// The numbers are only read from the Canton keys, the bare "remaining" only
+	// inside AboveTrafficLimit(...), so that free text such as "retries remaining: 2"
+	// is not mistaken for them
+	rejectionRequiredRe  = regexp.MustCompile(`\b(?:trafficCost|requiredTraffic)\s*[=:]\s*(\d+)`)
+	rejectionAvailableRe = regexp.MustCompile(`(?:\b(?:availableTraffic|remainingTraffic)\s*[=:]\s*|AboveTrafficLimit\([^)]*?\bremaining\s*=\s*)(\d+)`)
+)
+
+func parseTrafficRejection(message string, state *TrafficState, costDetails *EventCostDetails) *TrafficRejection {
+	rejection := &TrafficRejection{
+		Code: "INSUFFICIENT_TRAFFIC",
+	}
+	if match := rejectionCodeRe.FindStringSubmatch(message); len(match) > 1 {
+		rejection.Code = match[1]
+	}
+	rejection.Member = valueAfterAny(message, memberKeys)
+
+	// Fall back to the embedded cost details and traffic state when the
+	// message does not spell out the numbers
+	if match := rejectionRequiredRe.FindStringSubmatch(message); len(match) > 1 {
+		rejection.Required, _ = strconv.Atoi(match[1])
+	} else if costDetails != nil {
+		rejection.Required = costDetails.EventCost
+	}
+	if match := rejectionAvailableRe.FindStringSubmatch(message); len(match) > 1 {
+		rejection.Available, _ = strconv.Atoi(match[1])
+	} else if state != nil {
+		rejection.Available = state.AvailableTraffic()
+	}
+
+	return rejection
+}
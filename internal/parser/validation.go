@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+const (
+	// ValidationWarn logs the inconsistencies and keeps the event as it is
+	ValidationWarn = "warn"
+	// ValidationDrop refuses the event with ErrInconsistentCostDetails
+	ValidationDrop = "drop"
+	// ValidationTag keeps the event and lists the inconsistencies in validation_errors
+	ValidationTag = "tag"
+)
+
+// Validate checks that the parsed numbers are coherent with each other and
+// returns the list of inconsistencies. An inconsistency usually means that the
+// Canton log format changed and the parser needs to be updated.
+func (d *EventCostDetails) Validate() []string {
+	var problems []string
+
+	if d.EventCost <= 0 {
+		problems = append(problems, "event cost is missing")
+	}
+	if len(d.EnvelopesCost) == 0 {
+		problems = append(problems, "envelopes cost details are missing")
+		return problems
+	}
+
+	sum := 0
+	for i, envelope := range d.EnvelopesCost {
+		if envelope.FinalCost != envelope.WriteCost+envelope.ReadCost {
+			problems = append(problems, fmt.Sprintf(
+				"envelope %d: final cost %d != write cost %d + read cost %d",
+				i, envelope.FinalCost, envelope.WriteCost, envelope.ReadCost,
+			))
+		}
+		sum += envelope.FinalCost
+	}
+	if sum != d.EventCost {
+		problems = append(problems, fmt.Sprintf(
+			"event cost %d != sum of envelope final costs %d", d.EventCost, sum,
+		))
+	}
+
+	return problems
+}
+
+// validateCostDetails applies the configured validation mode to the parsed cost details
+func validateCostDetails(l *Line) error {
+	problems := l.CostDetails.Validate()
+	if len(problems) == 0 {
+		return nil
+	}
+
+	switch options.ValidationMode {
+	case ValidationDrop:
+		return fmt.Errorf("%w: %s", ErrInconsistentCostDetails, strings.Join(problems, "; "))
+	case ValidationTag:
+		l.CostDetails.ValidationErrors = problems
+	default:
+		slog.Warn("Inconsistent cost details",
+			slog.String("trace_id", l.TraceID),
+			slog.Any("validation_errors", problems),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package pricing
+
+import (
+	"context"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Enricher)(nil)
+
+// Enricher adds the estimated USD and Canton Coin price to the lines which carry
+// traffic costs, then passes them to the next exporter.
+type Enricher struct {
+	rates Rates
+	next  exporters.Exporter
+}
+
+func NewEnricher(rates Rates, next exporters.Exporter) *Enricher {
+	return &Enricher{
+		rates: rates,
+		next:  next,
+	}
+}
+
+func (e *Enricher) Export(ctx context.Context, line *parser.Line) error {
+	if traffic, ok := trafficOf(line); ok {
+		line.CostEstimate = e.Estimate(traffic, line)
+	}
+	return e.next.Export(ctx, line)
+}
+
+// Estimate prices the traffic with the rate effective at the timestamp of the line
+func (e *Enricher) Estimate(traffic float64, line *parser.Line) *parser.CostEstimate {
+	rate := e.rates.At(line.Timestamp)
+	usd := traffic / bytesPerMB * rate.USDPerMB
+	return &parser.CostEstimate{
+		Traffic:            traffic,
+		USD:                usd,
+		CC:                 usd / rate.USDPerCC,
+		USDPerMB:           rate.USDPerMB,
+		USDPerCC:           rate.USDPerCC,
+		RatesEffectiveFrom: rate.EffectiveFrom,
+	}
+}
+
+// trafficOf returns the traffic cost carried by the line
+func trafficOf(line *parser.Line) (float64, bool) {
+	switch line.Kind {
+	case parser.KindEventCost:
+		return float64(line.CostDetails.EventCost), true
+	case parser.KindTraceSummary:
+		return float64(line.TraceSummary.TotalCost), true
+	case parser.KindRollup:
+		return float64(line.Rollup.Sum), true
+	case parser.KindCounterpartyCost:
+		return line.CounterpartyCost.AttributedCost, true
+	}
+	return 0, false
+}
+
+func (e *Enricher) Start(ctx context.Context) error {
+	return e.next.Start(ctx)
+}
+
+func (e *Enricher) Flush(ctx context.Context) error {
+	return e.next.Flush(ctx)
+}
+
+func (e *Enricher) Close(ctx context.Context) error {
+	return e.next.Close(ctx)
+}
@@ -0,0 +1,8 @@
+package pricing
+
+import "errors"
+
+var (
+	ErrNoRates     = errors.New("no pricing rates defined")
+	ErrInvalidRate = errors.New("invalid pricing rate")
+)
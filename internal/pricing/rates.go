@@ -0,0 +1,72 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// bytesPerMB is the traffic unit of the USD per MB price
+const bytesPerMB = 1_000_000
+
+// Rate is the price of the traffic from its effective date until the next rate
+type Rate struct {
+	EffectiveFrom time.Time `json:"effective_from"`
+	// USDPerMB is the price of one megabyte (10^6 traffic units) of extra traffic
+	USDPerMB float64 `json:"usd_per_mb"`
+	// USDPerCC is the Canton Coin price
+	USDPerCC float64 `json:"usd_per_cc"`
+}
+
+// Rates is the rate history, ordered by effective date
+type Rates []Rate
+
+type ratesFile struct {
+	Rates Rates `json:"rates"`
+}
+
+// LoadRates reads the rate history from a JSON file:
+//
+//	{"rates": [{"effective_from": "2025-01-01T00:00:00Z", "usd_per_mb": 60, "usd_per_cc": 0.15}]}
+func LoadRates(path string) (Rates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file ratesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file %s: %w", path, err)
+	}
+	return NewRates(file.Rates...)
+}
+
+// NewRates validates and orders the rate history
+func NewRates(rates ...Rate) (Rates, error) {
+	if len(rates) == 0 {
+		return nil, ErrNoRates
+	}
+	for _, rate := range rates {
+		if rate.USDPerMB < 0 || rate.USDPerCC <= 0 {
+			return nil, fmt.Errorf("%w: effective from %s: usd_per_mb must not be negative and usd_per_cc must be positive",
+				ErrInvalidRate, rate.EffectiveFrom.Format(time.RFC3339))
+		}
+	}
+
+	sorted := make(Rates, len(rates))
+	copy(sorted, rates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].EffectiveFrom.Before(sorted[j].EffectiveFrom)
+	})
+	return sorted, nil
+}
+
+// At returns the rate effective at t. Timestamps before the first rate use the first one.
+func (r Rates) At(t time.Time) Rate {
+	i := sort.Search(len(r), func(i int) bool {
+		return r[i].EffectiveFrom.After(t)
+	})
+	return r[max(i-1, 0)]
+}
@@ -0,0 +1,45 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func TestRatesAt(t *testing.T) {
+	january := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	rates, err := NewRates(
+		Rate{EffectiveFrom: june, USDPerMB: 60, USDPerCC: 0.2},
+		Rate{EffectiveFrom: january, USDPerMB: 17, USDPerCC: 0.1},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create rates: %v", err)
+	}
+
+	cases := []struct {
+		at       time.Time
+		usdPerMB float64
+	}{
+		{january.Add(-time.Hour), 17},
+		{january, 17},
+		{june.Add(-time.Nanosecond), 17},
+		{june, 60},
+		{june.AddDate(1, 0, 0), 60},
+	}
+	for _, c := range cases {
+		if rate := rates.At(c.at); rate.USDPerMB != c.usdPerMB {
+			t.Errorf("Rate at %s mismatch: got %f, want %f", c.at, rate.USDPerMB, c.usdPerMB)
+		}
+	}
+
+	estimate := NewEnricher(rates, nil).Estimate(500_000, &parser.Line{Timestamp: june})
+	if estimate.USD != 30 || estimate.CC != 150 {
+		t.Errorf("Estimate mismatch: got %+v", estimate)
+	}
+
+	if _, err := NewRates(Rate{USDPerMB: 60}); err == nil {
+		t.Errorf("Rate without CC price should be invalid")
+	}
+}
@@ -0,0 +1,174 @@
+package rollup
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Engine)(nil)
+
+// Engine aggregates the EventCost lines into tumbling windows of the event
+// timestamps, keyed by participant, synchronizer and span name, and exports the
+// closed windows as Rollup lines.
+//
+// A window is closed once the watermark passes its end. The watermark is the
+// latest event timestamp (or the wall clock, if it is ahead) minus the allowed
+// lateness. Lines which arrive after their window was closed are dropped and
+// counted as late.
+//
+// The percentiles are approximate, within 1% of the exact ones, so that the
+// memory of a window does not grow with the number of its events, see sketch.
+type Engine struct {
+	Windows         []time.Duration `json:"windows"`
+	AllowedLateness time.Duration   `json:"allowed_lateness"`
+	exporters.Windowed
+
+	next      exporters.Exporter
+	buckets   map[bucketKey]*sketch
+	latest    time.Time
+	watermark time.Time
+	late      int
+	mutex     *sync.Mutex
+	now       func() time.Time
+}
+
+type bucketKey struct {
+	window       time.Duration
+	start        time.Time
+	participant  string
+	synchronizer string
+	spanName     string
+}
+
+func New(windows []time.Duration, allowedLateness time.Duration, next exporters.Exporter) *Engine {
+	e := &Engine{
+		Windows:         windows,
+		AllowedLateness: allowedLateness,
+		next:            next,
+		buckets:         make(map[bucketKey]*sketch),
+		mutex:           &sync.Mutex{},
+		now:             time.Now,
+	}
+	e.Windowed = exporters.NewWindowed(tickInterval(windows), e.flush)
+	return e
+}
+
+// Export adds the cost of the EventCost line to its windows, other lines are ignored
+func (e *Engine) Export(ctx context.Context, line *parser.Line) error {
+	if line.Kind != parser.KindEventCost {
+		return nil
+	}
+
+	timestamp := line.Timestamp
+	if timestamp.IsZero() {
+		timestamp = e.now()
+	}
+
+	e.mutex.Lock()
+	if timestamp.After(e.latest) {
+		e.latest = timestamp
+	}
+	for _, window := range e.Windows {
+		start := timestamp.Truncate(window)
+		if !start.Add(window).After(e.watermark) {
+			e.late++
+			slog.DebugContext(ctx, "Dropped late event from rollup",
+				slog.String("trace_id", line.TraceID),
+				slog.Duration("window", window),
+				slog.Time("timestamp", timestamp),
+			)
+			continue
+		}
+		key := bucketKey{
+			window:       window,
+			start:        start,
+			participant:  line.Participant,
+			synchronizer: line.Synchronizer,
+			spanName:     line.SpanName,
+		}
+		bucket, ok := e.buckets[key]
+		if !ok {
+			bucket = newSketch()
+			e.buckets[key] = bucket
+		}
+		bucket.add(line.CostDetails.EventCost)
+	}
+	e.mutex.Unlock()
+
+	return nil
+}
+
+// Late returns the number of lines which arrived after their window was closed
+func (e *Engine) Late() int {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.late
+}
+
+func (e *Engine) flush(ctx context.Context, all bool) {
+	var closed []parser.Rollup
+
+	e.mutex.Lock()
+	reference := e.now()
+	if e.latest.After(reference) {
+		reference = e.latest
+	}
+	e.watermark = reference.Add(-e.AllowedLateness)
+	for key, costs := range e.buckets {
+		if !all && key.start.Add(key.window).After(e.watermark) {
+			continue
+		}
+		closed = append(closed, summarize(key, costs))
+		delete(e.buckets, key)
+	}
+	e.mutex.Unlock()
+
+	sort.Slice(closed, func(i, j int) bool {
+		return closed[i].WindowEnd.Before(closed[j].WindowEnd)
+	})
+	for i := range closed {
+		line := &parser.Line{
+			Timestamp:    closed[i].WindowEnd,
+			SpanName:     closed[i].SpanName,
+			Participant:  closed[i].Participant,
+			Synchronizer: closed[i].Synchronizer,
+			Kind:         parser.KindRollup,
+			Rollup:       &closed[i],
+		}
+		if err := e.next.Export(ctx, line); err != nil {
+			slog.ErrorContext(ctx, "Failed to export rollup",
+				slog.String("window", closed[i].Window), slog.Any("error", err))
+		}
+	}
+}
+
+func summarize(key bucketKey, costs *sketch) parser.Rollup {
+	return parser.Rollup{
+		Participant:  key.participant,
+		Synchronizer: key.synchronizer,
+		SpanName:     key.spanName,
+		Window:       key.window.String(),
+		WindowStart:  key.start,
+		WindowEnd:    key.start.Add(key.window),
+		Count:        costs.count,
+		Sum:          costs.sum,
+		Min:          costs.min,
+		Max:          costs.max,
+		P50:          costs.percentile(0.50),
+		P95:          costs.percentile(0.95),
+		P99:          costs.percentile(0.99),
+	}
+}
+
+// tickInterval closes the shortest windows at most 10% late
+func tickInterval(windows []time.Duration) time.Duration {
+	shortest := slices.Min(windows)
+	return max(shortest/10, 100*time.Millisecond)
+}
@@ -0,0 +1,83 @@
+package rollup
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters/exporterstest"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func costLine(timestamp time.Time, cost int) *parser.Line {
+	return &parser.Line{
+		Timestamp:    timestamp,
+		SpanName:     "SequencerClient.sendAsync",
+		Participant:  "participant",
+		Synchronizer: "global-domain::1220be58c29e",
+		Kind:         parser.KindEventCost,
+		CostDetails:  &parser.EventCostDetails{EventCost: cost},
+	}
+}
+
+func TestEngine(t *testing.T) {
+	capture := &exporterstest.Capture{}
+	engine := New([]time.Duration{time.Minute}, 10*time.Second, capture)
+	start := time.Date(2025, 12, 3, 17, 5, 0, 0, time.UTC)
+	now := start
+	engine.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 1; i <= 100; i++ {
+		_ = engine.Export(ctx, costLine(start.Add(time.Duration(i)*100*time.Millisecond), i))
+	}
+
+	// The window ends at 17:06:00, it is closed once the watermark passes it
+	now = start.Add(time.Minute + 5*time.Second)
+	engine.flush(ctx, false)
+	if len(capture.Lines) != 0 {
+		t.Fatalf("Window should still be open, got %d rollups", len(capture.Lines))
+	}
+
+	now = start.Add(time.Minute + 10*time.Second)
+	engine.flush(ctx, false)
+	if len(capture.Lines) != 1 {
+		t.Fatalf("Rollup count mismatch: got %d, want 1", len(capture.Lines))
+	}
+
+	rollup := capture.Lines[0].Rollup
+	if rollup.Count != 100 || rollup.Sum != 5050 || rollup.Min != 1 || rollup.Max != 100 {
+		t.Errorf("Rollup mismatch: got %+v", rollup)
+	}
+	for _, percentile := range []struct{ got, want int }{{rollup.P50, 50}, {rollup.P95, 95}, {rollup.P99, 99}} {
+		if math.Abs(float64(percentile.got-percentile.want)) > relativeAccuracy*float64(percentile.want) {
+			t.Errorf("Percentile mismatch: got %d, want %d within 1%%", percentile.got, percentile.want)
+		}
+	}
+	if rollup.Participant != "participant" || rollup.Synchronizer != "global-domain::1220be58c29e" || rollup.Window != "1m0s" {
+		t.Errorf("Rollup key mismatch: got %+v", rollup)
+	}
+
+	// A line of the closed window is late
+	_ = engine.Export(ctx, costLine(start.Add(30*time.Second), 10))
+	if engine.Late() != 1 {
+		t.Errorf("Late count mismatch: got %d, want 1", engine.Late())
+	}
+}
+
+func TestSketchAccuracy(t *testing.T) {
+	costs := newSketch()
+	for cost := 0; cost <= 1_000_000; cost += 7 {
+		costs.add(cost)
+	}
+	if len(costs.buckets) > 1000 {
+		t.Errorf("sketch uses %d buckets, want at most 1000", len(costs.buckets))
+	}
+	for _, p := range []float64{0.01, 0.5, 0.95, 0.99, 1} {
+		exact := 7 * (int(math.Ceil(p*float64(costs.count))) - 1)
+		if got := costs.percentile(p); math.Abs(float64(got-exact)) > relativeAccuracy*float64(exact)+1 {
+			t.Errorf("percentile(%v) = %d, want %d within 1%%", p, got, exact)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package rollup
+
+import (
+	"math"
+	"slices"
+)
+
+// relativeAccuracy bounds the error of the percentiles of a rollup: a reported
+// percentile is within 1% of the exact one
+const relativeAccuracy = 0.01
+
+var logGamma = math.Log((1 + relativeAccuracy) / (1 - relativeAccuracy))
+
+// sketch summarizes the costs of a window in logarithmic buckets, bucket i
+// holding the values in (gamma^(i-1), gamma^i]. Its size depends on the range of
+// the values rather than on their number: costs up to 10^9 span about 1000
+// buckets. Count, sum, min and max are exact.
+type sketch struct {
+	buckets map[int]int
+	zeros   int
+	count   int
+	sum     int
+	min     int
+	max     int
+}
+
+func newSketch() *sketch {
+	return &sketch{buckets: make(map[int]int)}
+}
+
+func (s *sketch) add(value int) {
+	if s.count == 0 || value < s.min {
+		s.min = value
+	}
+	if s.count == 0 || value > s.max {
+		s.max = value
+	}
+	s.count++
+	s.sum += value
+
+	if value <= 0 {
+		s.zeros++
+		return
+	}
+	s.buckets[int(math.Ceil(math.Log(float64(value))/logGamma))]++
+}
+
+// percentile returns the nearest-rank percentile, within relativeAccuracy
+func (s *sketch) percentile(p float64) int {
+	rank := max(int(math.Ceil(p*float64(s.count))), 1)
+	if rank <= s.zeros {
+		return s.min
+	}
+	seen := s.zeros
+
+	indexes := make([]int, 0, len(s.buckets))
+	for i := range s.buckets {
+		indexes = append(indexes, i)
+	}
+	slices.Sort(indexes)
+	for _, i := range indexes {
+		seen += s.buckets[i]
+		if seen >= rank {
+			// The middle of the bucket, in relative terms, is within
+			// relativeAccuracy of any of its values
+			value := int(math.Round(2 * math.Exp(float64(i)*logGamma) / (1 + math.Exp(logGamma))))
+			return min(max(value, s.min), s.max)
+		}
+	}
+	return s.max
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Store)(nil)
+
+// Store keeps the recently parsed lines in memory, so they can be queried. It
+// holds at most Capacity lines, each for at most TTL; the oldest ones are evicted first.
+type Store struct {
+	exporters.NopLifecycle
+
+	Capacity int           `json:"capacity"`
+	TTL      time.Duration `json:"ttl"`
+
+	// entries is a ring buffer, head is the oldest entry
+	entries []entry
+	head    int
+	size    int
+	mutex   *sync.RWMutex
+	now     func() time.Time
+}
+
+type entry struct {
+	line     parser.Line
+	storedAt time.Time
+}
+
+// Totals are the aggregated costs of the lines matching a filter
+type Totals struct {
+	Count          int       `json:"count"`
+	EventCount     int       `json:"event_count"`
+	TotalCost      int       `json:"total_cost"`
+	MinCost        int       `json:"min_cost"`
+	MaxCost        int       `json:"max_cost"`
+	AverageCost    float64   `json:"average_cost"`
+	EnvelopeCount  int       `json:"envelope_count"`
+	FirstTimestamp time.Time `json:"first_timestamp"`
+	LastTimestamp  time.Time `json:"last_timestamp"`
+}
+
+func New(capacity int, ttl time.Duration) *Store {
+	return &Store{
+		Capacity: capacity,
+		TTL:      ttl,
+		entries:  make([]entry, capacity),
+		mutex:    &sync.RWMutex{},
+		now:      time.Now,
+	}
+}
+
+// Export stores a copy of the line
+func (s *Store) Export(ctx context.Context, line *parser.Line) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	s.evictExpired(now)
+	if s.size == s.Capacity {
+		s.head = (s.head + 1) % s.Capacity
+		s.size--
+	}
+	s.entries[(s.head+s.size)%s.Capacity] = entry{line: *line, storedAt: now}
+	s.size++
+
+	return nil
+}
+
+func (s *Store) evictExpired(now time.Time) {
+	for s.size > 0 && now.Sub(s.entries[s.head].storedAt) > s.TTL {
+		s.entries[s.head] = entry{}
+		s.head = (s.head + 1) % s.Capacity
+		s.size--
+	}
+}
+
+// each calls fn with the live lines matching the filter, the newest first, until fn returns false
+func (s *Store) each(f *filter.Filter, fn func(*parser.Line) bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	now := s.now()
+	for i := s.size - 1; i >= 0; i-- {
+		e := &s.entries[(s.head+i)%s.Capacity]
+		if now.Sub(e.storedAt) > s.TTL {
+			// The older entries are expired too
+			return
+		}
+		if f.Match(&e.line) && !fn(&e.line) {
+			return
+		}
+	}
+}
+
+// Find returns up to limit lines matching the filter, the newest first. A
+// non-positive limit returns all of them.
+func (s *Store) Find(f filter.Filter, limit int) []*parser.MessageLine {
+	lines := make([]*parser.MessageLine, 0)
+	s.each(&f, func(line *parser.Line) bool {
+		lines = append(lines, line.ToMessageLine())
+		return limit <= 0 || len(lines) < limit
+	})
+	return lines
+}
+
+// Totals aggregates the costs of the lines matching the filter
+func (s *Store) Totals(f filter.Filter) Totals {
+	var totals Totals
+	s.each(&f, func(line *parser.Line) bool {
+		totals.Count++
+		if totals.FirstTimestamp.IsZero() || line.Timestamp.Before(totals.FirstTimestamp) {
+			totals.FirstTimestamp = line.Timestamp
+		}
+		if line.Timestamp.After(totals.LastTimestamp) {
+			totals.LastTimestamp = line.Timestamp
+		}
+		if line.CostDetails == nil {
+			return true
+		}
+
+		cost := line.CostDetails.EventCost
+		if totals.EventCount == 0 || cost < totals.MinCost {
+			totals.MinCost = cost
+		}
+		totals.MaxCost = max(totals.MaxCost, cost)
+		totals.EventCount++
+		totals.TotalCost += cost
+		totals.EnvelopeCount += len(line.CostDetails.EnvelopesCost)
+		return true
+	})
+	if totals.EventCount > 0 {
+		totals.AverageCost = float64(totals.TotalCost) / float64(totals.EventCount)
+	}
+	return totals
+}
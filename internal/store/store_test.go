@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func costLine(traceID string, cost int) *parser.Line {
+	return &parser.Line{
+		Timestamp:    time.Date(2025, 12, 3, 17, 5, 0, 0, time.UTC),
+		TraceID:      traceID,
+		Synchronizer: "global-domain::1220be58c29e",
+		Kind:         parser.KindEventCost,
+		CostDetails: &parser.EventCostDetails{
+			EventCost:     cost,
+			EnvelopesCost: []parser.EnvelopeCostDetails{{FinalCost: cost}},
+		},
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := New(3, time.Minute)
+	now := time.Date(2025, 12, 3, 17, 5, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	_ = store.Export(ctx, costLine("a", 100))
+	_ = store.Export(ctx, costLine("b", 200))
+	_ = store.Export(ctx, costLine("a", 300))
+	_ = store.Export(ctx, costLine("c", 400))
+
+	// The first line is evicted by the capacity
+	if got := store.Find(filter.Filter{TraceID: "a"}, 0); len(got) != 1 || got[0].CostDetails.EventCost != 300 {
+		t.Fatalf("Find(a) = %+v, want the line costing 300 only", got)
+	}
+	if got := store.Find(filter.Filter{}, 2); len(got) != 2 || got[0].TraceID != "c" || got[1].TraceID != "a" {
+		t.Fatalf("Find(limit 2) = %+v, want c then a", got)
+	}
+
+	totals := store.Totals(filter.Filter{MinCost: 250})
+	if totals.EventCount != 2 || totals.TotalCost != 700 || totals.MinCost != 300 || totals.MaxCost != 400 || totals.AverageCost != 350 || totals.EnvelopeCount != 2 {
+		t.Fatalf("Totals = %+v", totals)
+	}
+
+	// Every line expires after the TTL
+	now = now.Add(2 * time.Minute)
+	if got := store.Find(filter.Filter{}, 0); len(got) != 0 {
+		t.Fatalf("Find after the TTL = %d lines, want none", len(got))
+	}
+	_ = store.Export(ctx, costLine("d", 500))
+	if totals := store.Totals(filter.Filter{}); totals.Count != 1 || totals.TotalCost != 500 {
+		t.Fatalf("Totals after the TTL = %+v", totals)
+	}
+}
@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/DLC-link/cantcost/internal/exporters"
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+var _ exporters.Exporter = (*Broker)(nil)
+
+// Broker pushes every exported line to the subscribers whose filter matches it.
+// Each subscriber has a buffer of BufferSize lines; a subscriber which lets it
+// fill up is disconnected rather than slowing down the export pipeline.
+type Broker struct {
+	exporters.NopLifecycle
+
+	BufferSize int `json:"buffer_size"`
+
+	subscribers map[*Subscriber]struct{}
+	mutex       *sync.Mutex
+}
+
+// Subscriber receives the JSON encoded MessageLine of the matching lines
+type Subscriber struct {
+	filter filter.Filter
+	lines  chan []byte
+	// evicted is set before lines is closed, when the subscriber fell behind
+	evicted bool
+}
+
+func New(bufferSize int) *Broker {
+	return &Broker{
+		BufferSize:  bufferSize,
+		subscribers: make(map[*Subscriber]struct{}),
+		mutex:       &sync.Mutex{},
+	}
+}
+
+// Export sends the line to the matching subscribers, it never blocks
+func (b *Broker) Export(ctx context.Context, line *parser.Line) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// The line is encoded once, and only if someone wants it
+	var data []byte
+	for subscriber := range b.subscribers {
+		if !subscriber.filter.Match(line) {
+			continue
+		}
+		if data == nil {
+			var err error
+			if data, err = json.Marshal(line.ToMessageLine()); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case subscriber.lines <- data:
+		default:
+			slog.WarnContext(ctx, "Disconnecting slow stream subscriber", slog.Int("buffer_size", b.BufferSize))
+			subscriber.evicted = true
+			b.remove(subscriber)
+		}
+	}
+	return nil
+}
+
+// Close disconnects all the subscribers
+func (b *Broker) Close(ctx context.Context) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for subscriber := range b.subscribers {
+		b.remove(subscriber)
+	}
+	return nil
+}
+
+func (b *Broker) Subscribe(f filter.Filter) *Subscriber {
+	subscriber := &Subscriber{
+		filter: f,
+		lines:  make(chan []byte, b.BufferSize),
+	}
+
+	b.mutex.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mutex.Unlock()
+	return subscriber
+}
+
+// Unsubscribe stops sending lines to the subscriber, it is safe to call after a disconnection
+func (b *Broker) Unsubscribe(subscriber *Subscriber) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.remove(subscriber)
+}
+
+// Subscribers returns the number of connected subscribers
+func (b *Broker) Subscribers() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.subscribers)
+}
+
+func (b *Broker) remove(subscriber *Subscriber) {
+	if _, ok := b.subscribers[subscriber]; !ok {
+		return
+	}
+	delete(b.subscribers, subscriber)
+	close(subscriber.lines)
+}
+
+// Lines returns the channel of the matching lines, it is closed when the
+// subscriber is disconnected
+func (s *Subscriber) Lines() <-chan []byte {
+	return s.lines
+}
+
+// Evicted reports whether the subscriber was disconnected because it fell
+// behind, rather than by Unsubscribe or Close. It must only be called once Lines
+// is closed.
+func (s *Subscriber) Evicted() bool {
+	return s.evicted
+}
@@ -0,0 +1,50 @@
+package stream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DLC-link/cantcost/internal/filter"
+	"github.com/DLC-link/cantcost/internal/parser"
+)
+
+func TestBroker(t *testing.T) {
+	broker := New(2)
+	all := broker.Subscribe(filter.Filter{})
+	expensive := broker.Subscribe(filter.Filter{MinCost: 1000})
+
+	ctx := context.Background()
+	line := func(cost int) *parser.Line {
+		return &parser.Line{
+			Kind:        parser.KindEventCost,
+			CostDetails: &parser.EventCostDetails{EventCost: cost},
+		}
+	}
+	_ = broker.Export(ctx, line(5000))
+	_ = broker.Export(ctx, line(10))
+
+	if got := len(expensive.Lines()); got != 1 {
+		t.Fatalf("filtered subscriber got %d lines, want 1", got)
+	}
+	if got := len(all.Lines()); got != 2 {
+		t.Fatalf("subscriber got %d lines, want 2", got)
+	}
+
+	// The buffer of the first subscriber is full, it is disconnected
+	_ = broker.Export(ctx, line(2000))
+	if got := broker.Subscribers(); got != 1 {
+		t.Fatalf("Subscribers() = %d, want 1", got)
+	}
+	for range all.Lines() {
+	}
+	if got := len(expensive.Lines()); got != 2 {
+		t.Fatalf("filtered subscriber got %d lines, want 2", got)
+	}
+
+	// Unsubscribing after a disconnection is a no-op
+	broker.Unsubscribe(all)
+	broker.Unsubscribe(expensive)
+	if got := broker.Subscribers(); got != 0 {
+		t.Fatalf("Subscribers() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,62 @@
+package stream
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WriteTimeout bounds a write to a stream client, a client which does not read
+// is disconnected
+const WriteTimeout = 10 * time.Second
+
+// WriteEvent writes a server-sent event, data must be a single line
+func WriteEvent(w io.Writer, event string, data []byte) error {
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
+
+// WriteComment writes a server-sent events comment, which keeps the connection alive
+func WriteComment(w io.Writer, comment string) error {
+	_, err := fmt.Fprintf(w, ": %s\n\n", comment)
+	return err
+}
+
+// EventWriter writes the server-sent events of a response, every write is
+// bounded by WriteTimeout and flushed to the client
+type EventWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+}
+
+// NewEventWriter sends the headers of the event stream
+func NewEventWriter(w http.ResponseWriter) (*EventWriter, error) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	e := &EventWriter{w: w, controller: http.NewResponseController(w)}
+	if err := e.controller.Flush(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *EventWriter) Event(event string, data []byte) error {
+	return e.write(func() error { return WriteEvent(e.w, event, data) })
+}
+
+func (e *EventWriter) Comment(comment string) error {
+	return e.write(func() error { return WriteComment(e.w, comment) })
+}
+
+func (e *EventWriter) write(write func() error) error {
+	// Not every ResponseWriter supports deadlines, e.g. in tests
+	_ = e.controller.SetWriteDeadline(time.Now().Add(WriteTimeout))
+	if err := write(); err != nil {
+		return err
+	}
+	return e.controller.Flush()
+}
@@ -0,0 +1,5 @@
+package version
+
+// Version is the version of the cantcost build, set at build time with
+// -ldflags "-X github.com/DLC-link/cantcost/internal/version.Version=v1.2.3"
+var Version = "dev"
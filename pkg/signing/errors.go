@@ -0,0 +1,11 @@
+package signing
+
+import "errors"
+
+var (
+	ErrMissingHeader    = errors.New("missing signature header")
+	ErrInvalidTimestamp = errors.New("invalid signature timestamp")
+	ErrExpired          = errors.New("signature timestamp out of tolerance")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrReplayed         = errors.New("replayed request")
+)
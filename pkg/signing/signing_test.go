@@ -0,0 +1,102 @@
+package signing
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret := []byte("shared secret")
+	now := time.Date(2025, 12, 3, 17, 0, 0, 0, time.UTC)
+	signer := NewSigner(secret)
+	signer.now = func() time.Time { return now }
+	verifier := NewVerifier(secret, time.Minute)
+	verifier.now = func() time.Time { return now.Add(10 * time.Second) }
+
+	body := []byte(`{"count":1,"lines":[]}`)
+	req := httptest.NewRequest(http.MethodPost, "/ingest", nil)
+	if err := signer.Sign(req, body); err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+
+	if err := verifier.Verify(req.Header, body); err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if err := verifier.Verify(req.Header, body); !errors.Is(err, ErrReplayed) {
+		t.Errorf("Verify() of a replay = %v, want %v", err, ErrReplayed)
+	}
+	if err := verifier.Verify(req.Header, []byte(`{"count":2}`)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() of a tampered body = %v, want %v", err, ErrInvalidSignature)
+	}
+	other := NewVerifier([]byte("other secret"), time.Minute)
+	other.now = verifier.now
+	if err := other.Verify(req.Header, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() with another secret = %v, want %v", err, ErrInvalidSignature)
+	}
+
+	late := NewVerifier(secret, time.Minute)
+	late.now = func() time.Time { return now.Add(2 * time.Minute) }
+	if err := late.Verify(req.Header, body); !errors.Is(err, ErrExpired) {
+		t.Errorf("Verify() of an old request = %v, want %v", err, ErrExpired)
+	}
+	if err := verifier.Verify(http.Header{}, body); !errors.Is(err, ErrMissingHeader) {
+		t.Errorf("Verify() without headers = %v, want %v", err, ErrMissingHeader)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	secret := []byte("shared secret")
+	var received string
+	handler := NewVerifier(secret, time.Minute).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		received = string(data)
+	}))
+
+	body := `{"count":1,"lines":[]}`
+	req := httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader(body))
+	if err := NewSigner(secret).Sign(req, []byte(body)); err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK || received != body {
+		t.Errorf("signed request: status %d, body %q", recorder.Code, received)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader(body)))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned request: status %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestVerifierForgetsExpiredNonces(t *testing.T) {
+	secret := []byte("shared secret")
+	now := time.Date(2025, 12, 3, 17, 0, 0, 0, time.UTC)
+	signer := NewSigner(secret)
+	signer.now = func() time.Time { return now }
+	verifier := NewVerifier(secret, time.Minute)
+	verifier.now = func() time.Time { return now }
+
+	body := []byte(`{"count":1,"lines":[]}`)
+	for range 4 {
+		req := httptest.NewRequest(http.MethodPost, "/ingest", nil)
+		if err := signer.Sign(req, body); err != nil {
+			t.Fatalf("Sign() error: %v", err)
+		}
+		if err := verifier.Verify(req.Header, body); err != nil {
+			t.Fatalf("Verify() error: %v", err)
+		}
+		now = now.Add(45 * time.Second)
+	}
+
+	// The first nonce was older than twice the tolerance on the last Verify
+	if len(verifier.nonces) != 3 || len(verifier.expiries) != 3 {
+		t.Errorf("%d nonces and %d expiries are kept, want 3", len(verifier.nonces), len(verifier.expiries))
+	}
+}
@@ -0,0 +1,110 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBodySize bounds the body read by Middleware
+const DefaultMaxBodySize = 10 << 20
+
+// Verifier checks the signature headers of the requests and rejects the
+// replayed ones. The nonces are remembered for twice the Tolerance, which is
+// the window of the accepted timestamps, so the memory is bounded by the
+// request rate.
+type Verifier struct {
+	// Tolerance is the maximum difference between the timestamp of a request and
+	// the clock of the receiver
+	Tolerance time.Duration
+
+	secret []byte
+	nonces map[string]time.Time
+	// expiries holds the nonces in the order they were seen, so the expired ones
+	// are evicted from the front
+	expiries []seenNonce
+	mutex    *sync.Mutex
+	now      func() time.Time
+}
+
+type seenNonce struct {
+	nonce string
+	at    time.Time
+}
+
+func NewVerifier(secret []byte, tolerance time.Duration) *Verifier {
+	return &Verifier{
+		Tolerance: tolerance,
+		secret:    secret,
+		nonces:    make(map[string]time.Time),
+		mutex:     &sync.Mutex{},
+		now:       time.Now,
+	}
+}
+
+// Verify checks the signature headers of the body, and that the nonce was not
+// seen already
+func (v *Verifier) Verify(header http.Header, body []byte) error {
+	timestamp := header.Get(TimestampHeader)
+	nonce := header.Get(NonceHeader)
+	signature := header.Get(SignatureHeader)
+	if timestamp == "" || nonce == "" || signature == "" {
+		return ErrMissingHeader
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidTimestamp, timestamp)
+	}
+	now := v.now()
+	if skew := now.Sub(time.Unix(seconds, 0)).Abs(); skew > v.Tolerance {
+		return fmt.Errorf("%w: %s away from now", ErrExpired, skew)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Signature(v.secret, timestamp, nonce, body))) {
+		return ErrInvalidSignature
+	}
+
+	// The nonce is only recorded once the signature is valid, so that forged
+	// requests cannot fill the memory
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	expired := 0
+	for _, seen := range v.expiries {
+		if now.Sub(seen.at) <= 2*v.Tolerance {
+			break
+		}
+		delete(v.nonces, seen.nonce)
+		expired++
+	}
+	v.expiries = v.expiries[expired:]
+	if _, ok := v.nonces[nonce]; ok {
+		return ErrReplayed
+	}
+	v.nonces[nonce] = now
+	v.expiries = append(v.expiries, seenNonce{nonce: nonce, at: now})
+	return nil
+}
+
+// Middleware answers 401 to the requests which fail Verify, and passes the
+// others to next with their body intact
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, DefaultMaxBodySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err := v.Verify(r.Header, body); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
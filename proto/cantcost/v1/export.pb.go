@@ -0,0 +1,1727 @@
+// The body of the cantcost HTTP exporter requests with the protobuf encoding,
+// sent with the Content-Type application/x-protobuf. The messages mirror the
+// JSON MessageLine, see internal/parser. The timestamps are nanoseconds since
+// the Unix epoch, 0 when they are not set.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: cantcost/v1/export.proto
+
+package cantcostv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Lines         []*Line                `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRequest) ProtoMessage() {}
+
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExportRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ExportRequest) GetLines() []*Line {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+type Line struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp        int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Message          string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	LoggerName       string                 `protobuf:"bytes,3,opt,name=logger_name,json=loggerName,proto3" json:"logger_name,omitempty"`
+	ThreadName       string                 `protobuf:"bytes,4,opt,name=thread_name,json=threadName,proto3" json:"thread_name,omitempty"`
+	Level            string                 `protobuf:"bytes,5,opt,name=level,proto3" json:"level,omitempty"`
+	SpanId           string                 `protobuf:"bytes,6,opt,name=span_id,json=spanId,proto3" json:"span_id,omitempty"`
+	SpanParentId     string                 `protobuf:"bytes,7,opt,name=span_parent_id,json=spanParentId,proto3" json:"span_parent_id,omitempty"`
+	TraceId          string                 `protobuf:"bytes,8,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	SpanName         string                 `protobuf:"bytes,9,opt,name=span_name,json=spanName,proto3" json:"span_name,omitempty"`
+	Participant      string                 `protobuf:"bytes,10,opt,name=participant,proto3" json:"participant,omitempty"`
+	Synchronizer     string                 `protobuf:"bytes,11,opt,name=synchronizer,proto3" json:"synchronizer,omitempty"`
+	Kind             string                 `protobuf:"bytes,12,opt,name=kind,proto3" json:"kind,omitempty"`
+	CostDetails      *EventCostDetails      `protobuf:"bytes,13,opt,name=cost_details,json=costDetails,proto3" json:"cost_details,omitempty"`
+	TrafficState     *TrafficState          `protobuf:"bytes,14,opt,name=traffic_state,json=trafficState,proto3" json:"traffic_state,omitempty"`
+	TrafficReceipt   *TrafficReceipt        `protobuf:"bytes,15,opt,name=traffic_receipt,json=trafficReceipt,proto3" json:"traffic_receipt,omitempty"`
+	TrafficPurchased *TrafficPurchased      `protobuf:"bytes,16,opt,name=traffic_purchased,json=trafficPurchased,proto3" json:"traffic_purchased,omitempty"`
+	TrafficRejection *TrafficRejection      `protobuf:"bytes,17,opt,name=traffic_rejection,json=trafficRejection,proto3" json:"traffic_rejection,omitempty"`
+	TraceSummary     *TraceSummary          `protobuf:"bytes,18,opt,name=trace_summary,json=traceSummary,proto3" json:"trace_summary,omitempty"`
+	CounterpartyCost *CounterpartyCost      `protobuf:"bytes,19,opt,name=counterparty_cost,json=counterpartyCost,proto3" json:"counterparty_cost,omitempty"`
+	Rollup           *Rollup                `protobuf:"bytes,20,opt,name=rollup,proto3" json:"rollup,omitempty"`
+	Anomaly          *Anomaly               `protobuf:"bytes,21,opt,name=anomaly,proto3" json:"anomaly,omitempty"`
+	CostEstimate     *CostEstimate          `protobuf:"bytes,22,opt,name=cost_estimate,json=costEstimate,proto3" json:"cost_estimate,omitempty"`
+	Submission       *Submission            `protobuf:"bytes,23,opt,name=submission,proto3" json:"submission,omitempty"`
+	// The JSON object of the projected fields, when the exporter has a
+	// projection. The other fields are not set then.
+	ProjectedJson []byte `protobuf:"bytes,100,opt,name=projected_json,json=projectedJson,proto3" json:"projected_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Line) Reset() {
+	*x = Line{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Line) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Line) ProtoMessage() {}
+
+func (x *Line) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Line.ProtoReflect.Descriptor instead.
+func (*Line) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Line) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Line) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Line) GetLoggerName() string {
+	if x != nil {
+		return x.LoggerName
+	}
+	return ""
+}
+
+func (x *Line) GetThreadName() string {
+	if x != nil {
+		return x.ThreadName
+	}
+	return ""
+}
+
+func (x *Line) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *Line) GetSpanId() string {
+	if x != nil {
+		return x.SpanId
+	}
+	return ""
+}
+
+func (x *Line) GetSpanParentId() string {
+	if x != nil {
+		return x.SpanParentId
+	}
+	return ""
+}
+
+func (x *Line) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *Line) GetSpanName() string {
+	if x != nil {
+		return x.SpanName
+	}
+	return ""
+}
+
+func (x *Line) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *Line) GetSynchronizer() string {
+	if x != nil {
+		return x.Synchronizer
+	}
+	return ""
+}
+
+func (x *Line) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Line) GetCostDetails() *EventCostDetails {
+	if x != nil {
+		return x.CostDetails
+	}
+	return nil
+}
+
+func (x *Line) GetTrafficState() *TrafficState {
+	if x != nil {
+		return x.TrafficState
+	}
+	return nil
+}
+
+func (x *Line) GetTrafficReceipt() *TrafficReceipt {
+	if x != nil {
+		return x.TrafficReceipt
+	}
+	return nil
+}
+
+func (x *Line) GetTrafficPurchased() *TrafficPurchased {
+	if x != nil {
+		return x.TrafficPurchased
+	}
+	return nil
+}
+
+func (x *Line) GetTrafficRejection() *TrafficRejection {
+	if x != nil {
+		return x.TrafficRejection
+	}
+	return nil
+}
+
+func (x *Line) GetTraceSummary() *TraceSummary {
+	if x != nil {
+		return x.TraceSummary
+	}
+	return nil
+}
+
+func (x *Line) GetCounterpartyCost() *CounterpartyCost {
+	if x != nil {
+		return x.CounterpartyCost
+	}
+	return nil
+}
+
+func (x *Line) GetRollup() *Rollup {
+	if x != nil {
+		return x.Rollup
+	}
+	return nil
+}
+
+func (x *Line) GetAnomaly() *Anomaly {
+	if x != nil {
+		return x.Anomaly
+	}
+	return nil
+}
+
+func (x *Line) GetCostEstimate() *CostEstimate {
+	if x != nil {
+		return x.CostEstimate
+	}
+	return nil
+}
+
+func (x *Line) GetSubmission() *Submission {
+	if x != nil {
+		return x.Submission
+	}
+	return nil
+}
+
+func (x *Line) GetProjectedJson() []byte {
+	if x != nil {
+		return x.ProjectedJson
+	}
+	return nil
+}
+
+type Recipient struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Member        string                 `protobuf:"bytes,2,opt,name=member,proto3" json:"member,omitempty"`
+	GroupId       int64                  `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Recipient) Reset() {
+	*x = Recipient{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Recipient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recipient) ProtoMessage() {}
+
+func (x *Recipient) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recipient.ProtoReflect.Descriptor instead.
+func (*Recipient) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Recipient) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Recipient) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *Recipient) GetGroupId() int64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+type EnvelopeCostDetails struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WriteCost     int64                  `protobuf:"varint,1,opt,name=write_cost,json=writeCost,proto3" json:"write_cost,omitempty"`
+	ReadCost      int64                  `protobuf:"varint,2,opt,name=read_cost,json=readCost,proto3" json:"read_cost,omitempty"`
+	FinalCost     int64                  `protobuf:"varint,3,opt,name=final_cost,json=finalCost,proto3" json:"final_cost,omitempty"`
+	Recipients    []*Recipient           `protobuf:"bytes,4,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvelopeCostDetails) Reset() {
+	*x = EnvelopeCostDetails{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvelopeCostDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvelopeCostDetails) ProtoMessage() {}
+
+func (x *EnvelopeCostDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvelopeCostDetails.ProtoReflect.Descriptor instead.
+func (*EnvelopeCostDetails) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EnvelopeCostDetails) GetWriteCost() int64 {
+	if x != nil {
+		return x.WriteCost
+	}
+	return 0
+}
+
+func (x *EnvelopeCostDetails) GetReadCost() int64 {
+	if x != nil {
+		return x.ReadCost
+	}
+	return 0
+}
+
+func (x *EnvelopeCostDetails) GetFinalCost() int64 {
+	if x != nil {
+		return x.FinalCost
+	}
+	return 0
+}
+
+func (x *EnvelopeCostDetails) GetRecipients() []*Recipient {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+type EventCostDetails struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	EventCost          int64                  `protobuf:"varint,1,opt,name=event_cost,json=eventCost,proto3" json:"event_cost,omitempty"`
+	CostMultiplier     int64                  `protobuf:"varint,2,opt,name=cost_multiplier,json=costMultiplier,proto3" json:"cost_multiplier,omitempty"`
+	GroupToMembersSize map[int64]int64        `protobuf:"bytes,3,rep,name=group_to_members_size,json=groupToMembersSize,proto3" json:"group_to_members_size,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	EnvelopesCost      []*EnvelopeCostDetails `protobuf:"bytes,4,rep,name=envelopes_cost,json=envelopesCost,proto3" json:"envelopes_cost,omitempty"`
+	ValidationErrors   []string               `protobuf:"bytes,5,rep,name=validation_errors,json=validationErrors,proto3" json:"validation_errors,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *EventCostDetails) Reset() {
+	*x = EventCostDetails{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventCostDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventCostDetails) ProtoMessage() {}
+
+func (x *EventCostDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventCostDetails.ProtoReflect.Descriptor instead.
+func (*EventCostDetails) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EventCostDetails) GetEventCost() int64 {
+	if x != nil {
+		return x.EventCost
+	}
+	return 0
+}
+
+func (x *EventCostDetails) GetCostMultiplier() int64 {
+	if x != nil {
+		return x.CostMultiplier
+	}
+	return 0
+}
+
+func (x *EventCostDetails) GetGroupToMembersSize() map[int64]int64 {
+	if x != nil {
+		return x.GroupToMembersSize
+	}
+	return nil
+}
+
+func (x *EventCostDetails) GetEnvelopesCost() []*EnvelopeCostDetails {
+	if x != nil {
+		return x.EnvelopesCost
+	}
+	return nil
+}
+
+func (x *EventCostDetails) GetValidationErrors() []string {
+	if x != nil {
+		return x.ValidationErrors
+	}
+	return nil
+}
+
+type TrafficState struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	ExtraTrafficPurchased int64                  `protobuf:"varint,1,opt,name=extra_traffic_purchased,json=extraTrafficPurchased,proto3" json:"extra_traffic_purchased,omitempty"`
+	ExtraTrafficConsumed  int64                  `protobuf:"varint,2,opt,name=extra_traffic_consumed,json=extraTrafficConsumed,proto3" json:"extra_traffic_consumed,omitempty"`
+	BaseTrafficRemainder  int64                  `protobuf:"varint,3,opt,name=base_traffic_remainder,json=baseTrafficRemainder,proto3" json:"base_traffic_remainder,omitempty"`
+	LastConsumedCost      int64                  `protobuf:"varint,4,opt,name=last_consumed_cost,json=lastConsumedCost,proto3" json:"last_consumed_cost,omitempty"`
+	Timestamp             int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Serial                int64                  `protobuf:"varint,6,opt,name=serial,proto3" json:"serial,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *TrafficState) Reset() {
+	*x = TrafficState{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrafficState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrafficState) ProtoMessage() {}
+
+func (x *TrafficState) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrafficState.ProtoReflect.Descriptor instead.
+func (*TrafficState) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TrafficState) GetExtraTrafficPurchased() int64 {
+	if x != nil {
+		return x.ExtraTrafficPurchased
+	}
+	return 0
+}
+
+func (x *TrafficState) GetExtraTrafficConsumed() int64 {
+	if x != nil {
+		return x.ExtraTrafficConsumed
+	}
+	return 0
+}
+
+func (x *TrafficState) GetBaseTrafficRemainder() int64 {
+	if x != nil {
+		return x.BaseTrafficRemainder
+	}
+	return 0
+}
+
+func (x *TrafficState) GetLastConsumedCost() int64 {
+	if x != nil {
+		return x.LastConsumedCost
+	}
+	return 0
+}
+
+func (x *TrafficState) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TrafficState) GetSerial() int64 {
+	if x != nil {
+		return x.Serial
+	}
+	return 0
+}
+
+type TrafficReceipt struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	ConsumedCost         int64                  `protobuf:"varint,1,opt,name=consumed_cost,json=consumedCost,proto3" json:"consumed_cost,omitempty"`
+	ExtraTrafficConsumed int64                  `protobuf:"varint,2,opt,name=extra_traffic_consumed,json=extraTrafficConsumed,proto3" json:"extra_traffic_consumed,omitempty"`
+	BaseTrafficRemainder int64                  `protobuf:"varint,3,opt,name=base_traffic_remainder,json=baseTrafficRemainder,proto3" json:"base_traffic_remainder,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *TrafficReceipt) Reset() {
+	*x = TrafficReceipt{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrafficReceipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrafficReceipt) ProtoMessage() {}
+
+func (x *TrafficReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrafficReceipt.ProtoReflect.Descriptor instead.
+func (*TrafficReceipt) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TrafficReceipt) GetConsumedCost() int64 {
+	if x != nil {
+		return x.ConsumedCost
+	}
+	return 0
+}
+
+func (x *TrafficReceipt) GetExtraTrafficConsumed() int64 {
+	if x != nil {
+		return x.ExtraTrafficConsumed
+	}
+	return 0
+}
+
+func (x *TrafficReceipt) GetBaseTrafficRemainder() int64 {
+	if x != nil {
+		return x.BaseTrafficRemainder
+	}
+	return 0
+}
+
+type TrafficPurchased struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Member                string                 `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	Serial                int64                  `protobuf:"varint,2,opt,name=serial,proto3" json:"serial,omitempty"`
+	ExtraTrafficPurchased int64                  `protobuf:"varint,3,opt,name=extra_traffic_purchased,json=extraTrafficPurchased,proto3" json:"extra_traffic_purchased,omitempty"`
+	SequencingTimestamp   int64                  `protobuf:"varint,4,opt,name=sequencing_timestamp,json=sequencingTimestamp,proto3" json:"sequencing_timestamp,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *TrafficPurchased) Reset() {
+	*x = TrafficPurchased{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrafficPurchased) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrafficPurchased) ProtoMessage() {}
+
+func (x *TrafficPurchased) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrafficPurchased.ProtoReflect.Descriptor instead.
+func (*TrafficPurchased) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TrafficPurchased) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *TrafficPurchased) GetSerial() int64 {
+	if x != nil {
+		return x.Serial
+	}
+	return 0
+}
+
+func (x *TrafficPurchased) GetExtraTrafficPurchased() int64 {
+	if x != nil {
+		return x.ExtraTrafficPurchased
+	}
+	return 0
+}
+
+func (x *TrafficPurchased) GetSequencingTimestamp() int64 {
+	if x != nil {
+		return x.SequencingTimestamp
+	}
+	return 0
+}
+
+type TrafficRejection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Member        string                 `protobuf:"bytes,2,opt,name=member,proto3" json:"member,omitempty"`
+	Required      int64                  `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+	Available     int64                  `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrafficRejection) Reset() {
+	*x = TrafficRejection{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrafficRejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrafficRejection) ProtoMessage() {}
+
+func (x *TrafficRejection) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrafficRejection.ProtoReflect.Descriptor instead.
+func (*TrafficRejection) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TrafficRejection) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *TrafficRejection) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *TrafficRejection) GetRequired() int64 {
+	if x != nil {
+		return x.Required
+	}
+	return 0
+}
+
+func (x *TrafficRejection) GetAvailable() int64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+type TraceSummary struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TraceId            string                 `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	EventCount         int64                  `protobuf:"varint,2,opt,name=event_count,json=eventCount,proto3" json:"event_count,omitempty"`
+	TotalCost          int64                  `protobuf:"varint,3,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	EnvelopeCount      int64                  `protobuf:"varint,4,opt,name=envelope_count,json=envelopeCount,proto3" json:"envelope_count,omitempty"`
+	DistinctRecipients int64                  `protobuf:"varint,5,opt,name=distinct_recipients,json=distinctRecipients,proto3" json:"distinct_recipients,omitempty"`
+	FirstTimestamp     int64                  `protobuf:"varint,6,opt,name=first_timestamp,json=firstTimestamp,proto3" json:"first_timestamp,omitempty"`
+	LastTimestamp      int64                  `protobuf:"varint,7,opt,name=last_timestamp,json=lastTimestamp,proto3" json:"last_timestamp,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TraceSummary) Reset() {
+	*x = TraceSummary{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceSummary) ProtoMessage() {}
+
+func (x *TraceSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceSummary.ProtoReflect.Descriptor instead.
+func (*TraceSummary) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TraceSummary) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *TraceSummary) GetEventCount() int64 {
+	if x != nil {
+		return x.EventCount
+	}
+	return 0
+}
+
+func (x *TraceSummary) GetTotalCost() int64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
+func (x *TraceSummary) GetEnvelopeCount() int64 {
+	if x != nil {
+		return x.EnvelopeCount
+	}
+	return 0
+}
+
+func (x *TraceSummary) GetDistinctRecipients() int64 {
+	if x != nil {
+		return x.DistinctRecipients
+	}
+	return 0
+}
+
+func (x *TraceSummary) GetFirstTimestamp() int64 {
+	if x != nil {
+		return x.FirstTimestamp
+	}
+	return 0
+}
+
+func (x *TraceSummary) GetLastTimestamp() int64 {
+	if x != nil {
+		return x.LastTimestamp
+	}
+	return 0
+}
+
+type CounterpartyCost struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Counterparty   string                 `protobuf:"bytes,1,opt,name=counterparty,proto3" json:"counterparty,omitempty"`
+	Policy         string                 `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	WindowStart    int64                  `protobuf:"varint,3,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"`
+	WindowEnd      int64                  `protobuf:"varint,4,opt,name=window_end,json=windowEnd,proto3" json:"window_end,omitempty"`
+	AttributedCost float64                `protobuf:"fixed64,5,opt,name=attributed_cost,json=attributedCost,proto3" json:"attributed_cost,omitempty"`
+	EnvelopeCount  int64                  `protobuf:"varint,6,opt,name=envelope_count,json=envelopeCount,proto3" json:"envelope_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CounterpartyCost) Reset() {
+	*x = CounterpartyCost{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CounterpartyCost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CounterpartyCost) ProtoMessage() {}
+
+func (x *CounterpartyCost) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CounterpartyCost.ProtoReflect.Descriptor instead.
+func (*CounterpartyCost) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CounterpartyCost) GetCounterparty() string {
+	if x != nil {
+		return x.Counterparty
+	}
+	return ""
+}
+
+func (x *CounterpartyCost) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *CounterpartyCost) GetWindowStart() int64 {
+	if x != nil {
+		return x.WindowStart
+	}
+	return 0
+}
+
+func (x *CounterpartyCost) GetWindowEnd() int64 {
+	if x != nil {
+		return x.WindowEnd
+	}
+	return 0
+}
+
+func (x *CounterpartyCost) GetAttributedCost() float64 {
+	if x != nil {
+		return x.AttributedCost
+	}
+	return 0
+}
+
+func (x *CounterpartyCost) GetEnvelopeCount() int64 {
+	if x != nil {
+		return x.EnvelopeCount
+	}
+	return 0
+}
+
+type Rollup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Participant   string                 `protobuf:"bytes,1,opt,name=participant,proto3" json:"participant,omitempty"`
+	Synchronizer  string                 `protobuf:"bytes,2,opt,name=synchronizer,proto3" json:"synchronizer,omitempty"`
+	SpanName      string                 `protobuf:"bytes,3,opt,name=span_name,json=spanName,proto3" json:"span_name,omitempty"`
+	Window        string                 `protobuf:"bytes,4,opt,name=window,proto3" json:"window,omitempty"`
+	WindowStart   int64                  `protobuf:"varint,5,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"`
+	WindowEnd     int64                  `protobuf:"varint,6,opt,name=window_end,json=windowEnd,proto3" json:"window_end,omitempty"`
+	Count         int64                  `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`
+	Sum           int64                  `protobuf:"varint,8,opt,name=sum,proto3" json:"sum,omitempty"`
+	Min           int64                  `protobuf:"varint,9,opt,name=min,proto3" json:"min,omitempty"`
+	Max           int64                  `protobuf:"varint,10,opt,name=max,proto3" json:"max,omitempty"`
+	P50           int64                  `protobuf:"varint,11,opt,name=p50,proto3" json:"p50,omitempty"`
+	P95           int64                  `protobuf:"varint,12,opt,name=p95,proto3" json:"p95,omitempty"`
+	P99           int64                  `protobuf:"varint,13,opt,name=p99,proto3" json:"p99,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rollup) Reset() {
+	*x = Rollup{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rollup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rollup) ProtoMessage() {}
+
+func (x *Rollup) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rollup.ProtoReflect.Descriptor instead.
+func (*Rollup) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Rollup) GetParticipant() string {
+	if x != nil {
+		return x.Participant
+	}
+	return ""
+}
+
+func (x *Rollup) GetSynchronizer() string {
+	if x != nil {
+		return x.Synchronizer
+	}
+	return ""
+}
+
+func (x *Rollup) GetSpanName() string {
+	if x != nil {
+		return x.SpanName
+	}
+	return ""
+}
+
+func (x *Rollup) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *Rollup) GetWindowStart() int64 {
+	if x != nil {
+		return x.WindowStart
+	}
+	return 0
+}
+
+func (x *Rollup) GetWindowEnd() int64 {
+	if x != nil {
+		return x.WindowEnd
+	}
+	return 0
+}
+
+func (x *Rollup) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Rollup) GetSum() int64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *Rollup) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Rollup) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Rollup) GetP50() int64 {
+	if x != nil {
+		return x.P50
+	}
+	return 0
+}
+
+func (x *Rollup) GetP95() int64 {
+	if x != nil {
+		return x.P95
+	}
+	return 0
+}
+
+func (x *Rollup) GetP99() int64 {
+	if x != nil {
+		return x.P99
+	}
+	return 0
+}
+
+type Anomaly struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Key             string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Metric          string                 `protobuf:"bytes,2,opt,name=metric,proto3" json:"metric,omitempty"`
+	Method          string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Value           float64                `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	Mean            float64                `protobuf:"fixed64,5,opt,name=mean,proto3" json:"mean,omitempty"`
+	StdDev          float64                `protobuf:"fixed64,6,opt,name=std_dev,json=stdDev,proto3" json:"std_dev,omitempty"`
+	Score           float64                `protobuf:"fixed64,7,opt,name=score,proto3" json:"score,omitempty"`
+	BaselineSamples int64                  `protobuf:"varint,8,opt,name=baseline_samples,json=baselineSamples,proto3" json:"baseline_samples,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Anomaly) Reset() {
+	*x = Anomaly{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Anomaly) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Anomaly) ProtoMessage() {}
+
+func (x *Anomaly) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Anomaly.ProtoReflect.Descriptor instead.
+func (*Anomaly) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Anomaly) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Anomaly) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *Anomaly) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Anomaly) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Anomaly) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *Anomaly) GetStdDev() float64 {
+	if x != nil {
+		return x.StdDev
+	}
+	return 0
+}
+
+func (x *Anomaly) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Anomaly) GetBaselineSamples() int64 {
+	if x != nil {
+		return x.BaselineSamples
+	}
+	return 0
+}
+
+type CostEstimate struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Traffic            float64                `protobuf:"fixed64,1,opt,name=traffic,proto3" json:"traffic,omitempty"`
+	Usd                float64                `protobuf:"fixed64,2,opt,name=usd,proto3" json:"usd,omitempty"`
+	Cc                 float64                `protobuf:"fixed64,3,opt,name=cc,proto3" json:"cc,omitempty"`
+	UsdPerMb           float64                `protobuf:"fixed64,4,opt,name=usd_per_mb,json=usdPerMb,proto3" json:"usd_per_mb,omitempty"`
+	UsdPerCc           float64                `protobuf:"fixed64,5,opt,name=usd_per_cc,json=usdPerCc,proto3" json:"usd_per_cc,omitempty"`
+	RatesEffectiveFrom int64                  `protobuf:"varint,6,opt,name=rates_effective_from,json=ratesEffectiveFrom,proto3" json:"rates_effective_from,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CostEstimate) Reset() {
+	*x = CostEstimate{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostEstimate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostEstimate) ProtoMessage() {}
+
+func (x *CostEstimate) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostEstimate.ProtoReflect.Descriptor instead.
+func (*CostEstimate) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CostEstimate) GetTraffic() float64 {
+	if x != nil {
+		return x.Traffic
+	}
+	return 0
+}
+
+func (x *CostEstimate) GetUsd() float64 {
+	if x != nil {
+		return x.Usd
+	}
+	return 0
+}
+
+func (x *CostEstimate) GetCc() float64 {
+	if x != nil {
+		return x.Cc
+	}
+	return 0
+}
+
+func (x *CostEstimate) GetUsdPerMb() float64 {
+	if x != nil {
+		return x.UsdPerMb
+	}
+	return 0
+}
+
+func (x *CostEstimate) GetUsdPerCc() float64 {
+	if x != nil {
+		return x.UsdPerCc
+	}
+	return 0
+}
+
+func (x *CostEstimate) GetRatesEffectiveFrom() int64 {
+	if x != nil {
+		return x.RatesEffectiveFrom
+	}
+	return 0
+}
+
+type Submission struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TraceId       string                 `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	CommandId     string                 `protobuf:"bytes,2,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	SubmissionId  string                 `protobuf:"bytes,3,opt,name=submission_id,json=submissionId,proto3" json:"submission_id,omitempty"`
+	User          string                 `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	ApplicationId string                 `protobuf:"bytes,5,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	TemplateId    string                 `protobuf:"bytes,6,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Choice        string                 `protobuf:"bytes,7,opt,name=choice,proto3" json:"choice,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Submission) Reset() {
+	*x = Submission{}
+	mi := &file_cantcost_v1_export_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Submission) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Submission) ProtoMessage() {}
+
+func (x *Submission) ProtoReflect() protoreflect.Message {
+	mi := &file_cantcost_v1_export_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Submission.ProtoReflect.Descriptor instead.
+func (*Submission) Descriptor() ([]byte, []int) {
+	return file_cantcost_v1_export_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Submission) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *Submission) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *Submission) GetSubmissionId() string {
+	if x != nil {
+		return x.SubmissionId
+	}
+	return ""
+}
+
+func (x *Submission) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Submission) GetApplicationId() string {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return ""
+}
+
+func (x *Submission) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *Submission) GetChoice() string {
+	if x != nil {
+		return x.Choice
+	}
+	return ""
+}
+
+var File_cantcost_v1_export_proto protoreflect.FileDescriptor
+
+var file_cantcost_v1_export_proto_rawDesc = string([]byte{
+	0x0a, 0x18, 0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x65, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x63, 0x61, 0x6e, 0x74,
+	0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x22, 0x4e, 0x0a, 0x0d, 0x45, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x27,
+	0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x6e, 0x65,
+	0x52, 0x05, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x22, 0xd0, 0x08, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x6f, 0x67, 0x67,
+	0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c,
+	0x6f, 0x67, 0x67, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x68, 0x72,
+	0x65, 0x61, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65,
+	0x76, 0x65, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c,
+	0x12, 0x17, 0x0a, 0x07, 0x73, 0x70, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x70, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x70, 0x61,
+	0x6e, 0x5f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x73, 0x70, 0x61, 0x6e, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12,
+	0x19, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x70,
+	0x61, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x70, 0x61, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x61,
+	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x79, 0x6e,
+	0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x12, 0x40, 0x0a, 0x0c, 0x63, 0x6f, 0x73, 0x74, 0x5f, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f,
+	0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x73, 0x74, 0x44,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x0b, 0x63, 0x6f, 0x73, 0x74, 0x44, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x73, 0x12, 0x3e, 0x0a, 0x0d, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x61, 0x6e,
+	0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x44, 0x0a, 0x0f, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f, 0x72,
+	0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63,
+	0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x66, 0x66,
+	0x69, 0x63, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x66, 0x66,
+	0x69, 0x63, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x12, 0x4a, 0x0a, 0x11, 0x74, 0x72, 0x61,
+	0x66, 0x66, 0x69, 0x63, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x64, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61,
+	0x73, 0x65, 0x64, 0x52, 0x10, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x50, 0x75, 0x72, 0x63,
+	0x68, 0x61, 0x73, 0x65, 0x64, 0x12, 0x4a, 0x0a, 0x11, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x5f, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x10, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x3e, 0x0a, 0x0d, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x73, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x61, 0x6e, 0x74, 0x63,
+	0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x12, 0x4a, 0x0a, 0x11, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x61, 0x72, 0x74,
+	0x79, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x63,
+	0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x65, 0x72, 0x70, 0x61, 0x72, 0x74, 0x79, 0x43, 0x6f, 0x73, 0x74, 0x52, 0x10, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x65, 0x72, 0x70, 0x61, 0x72, 0x74, 0x79, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x2b, 0x0a,
+	0x06, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x6c,
+	0x75, 0x70, 0x52, 0x06, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x12, 0x2e, 0x0a, 0x07, 0x61, 0x6e,
+	0x6f, 0x6d, 0x61, 0x6c, 0x79, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x61,
+	0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6e, 0x6f, 0x6d, 0x61, 0x6c,
+	0x79, 0x52, 0x07, 0x61, 0x6e, 0x6f, 0x6d, 0x61, 0x6c, 0x79, 0x12, 0x3e, 0x0a, 0x0d, 0x63, 0x6f,
+	0x73, 0x74, 0x5f, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x73, 0x74, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x63, 0x6f,
+	0x73, 0x74, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x73, 0x75,
+	0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x64, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4a, 0x73, 0x6f, 0x6e, 0x22, 0x52, 0x0a, 0x09, 0x52, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x6d,
+	0x62, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x22, 0xa8,
+	0x01, 0x0a, 0x13, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x44,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f,
+	0x63, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x63, 0x6f,
+	0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f,
+	0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x73, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6f, 0x73,
+	0x74, 0x12, 0x36, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x72,
+	0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x81, 0x03, 0x0a, 0x10, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x43, 0x6f, 0x73, 0x74, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x1d,
+	0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x27, 0x0a,
+	0x0f, 0x63, 0x6f, 0x73, 0x74, 0x5f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x63, 0x6f, 0x73, 0x74, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x12, 0x68, 0x0a, 0x15, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f,
+	0x74, 0x6f, 0x5f, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x73, 0x74, 0x44, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x6f, 0x4d, 0x65, 0x6d, 0x62,
+	0x65, 0x72, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x12, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x54, 0x6f, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x47, 0x0a, 0x0e, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x73, 0x5f, 0x63, 0x6f,
+	0x73, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x61, 0x6e, 0x74, 0x63,
+	0x6f, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x43,
+	0x6f, 0x73, 0x74, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x0d, 0x65, 0x6e, 0x76, 0x65,
+	0x6c, 0x6f, 0x70, 0x65, 0x73, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x1a, 0x45, 0x0a, 0x17, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54,
+	0x6f, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x96, 0x02,
+	0x0a, 0x0c, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x36,
+	0x0a, 0x17, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f,
+	0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x15, 0x65, 0x78, 0x74, 0x72, 0x61, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x50, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x73, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x16, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f,
+	0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x65, 0x78, 0x74, 0x72, 0x61, 0x54, 0x72, 0x61,
+	0x66, 0x66, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x16,
+	0x62, 0x61, 0x73, 0x65, 0x5f, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f, 0x72, 0x65, 0x6d,
+	0x61, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x62, 0x61,
+	0x73, 0x65, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x64,
+	0x65, 0x72, 0x12, 0x2c, 0x0a, 0x12, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x75,
+	0x6d, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10,
+	0x6c, 0x61, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x73, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x22, 0xa1, 0x01, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x66, 0x66,
+	0x69, 0x63, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e,
+	0x73, 0x75, 0x6d, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x34,
+	0x0a, 0x16, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f,
+	0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14,
+	0x65, 0x78, 0x74, 0x72, 0x61, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x73,
+	0x75, 0x6d, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x16, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x74, 0x72, 0x61,
+	0x66, 0x66, 0x69, 0x63, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x62, 0x61, 0x73, 0x65, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69,
+	0x63, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x22, 0xad, 0x01, 0x0a, 0x10, 0x54,
+	0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12,
+	0x36, 0x0a, 0x17, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x15, 0x65, 0x78, 0x74, 0x72, 0x61, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x50, 0x75,
+	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x64, 0x12, 0x31, 0x0a, 0x14, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x69, 0x6e,
+	0x67, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x78, 0x0a, 0x10, 0x54, 0x72,
+	0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12,
+	0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61,
+	0x62, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c,
+	0x61, 0x62, 0x6c, 0x65, 0x22, 0x91, 0x02, 0x0a, 0x0c, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63, 0x65, 0x49, 0x64,
+	0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x73, 0x74,
+	0x12, 0x25, 0x0a, 0x0e, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f,
+	0x70, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x13, 0x64, 0x69, 0x73, 0x74, 0x69,
+	0x6e, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x52, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x66, 0x69, 0x72, 0x73,
+	0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0e, 0x66, 0x69, 0x72, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x25, 0x0a, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xe0, 0x01, 0x0a, 0x10, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x65, 0x72, 0x70, 0x61, 0x72, 0x74, 0x79, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x22, 0x0a,
+	0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x61, 0x72, 0x74, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x61, 0x72, 0x74,
+	0x79, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x45, 0x6e, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x61,
+	0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64,
+	0x43, 0x6f, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x65, 0x6e,
+	0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xc7, 0x02, 0x0a, 0x06,
+	0x52, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63,
+	0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x79, 0x6e, 0x63,
+	0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09,
+	0x73, 0x70, 0x61, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x70, 0x61, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x65,
+	0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x45, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x6d,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x6d,
+	0x69, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a,
+	0x03, 0x6d, 0x61, 0x78, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12,
+	0x10, 0x0a, 0x03, 0x70, 0x35, 0x30, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x70, 0x35,
+	0x30, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x39, 0x35, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03,
+	0x70, 0x39, 0x35, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x39, 0x39, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x03, 0x70, 0x39, 0x39, 0x22, 0xcf, 0x01, 0x0a, 0x07, 0x41, 0x6e, 0x6f, 0x6d, 0x61, 0x6c,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x6d,
+	0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x61,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12, 0x17, 0x0a,
+	0x07, 0x73, 0x74, 0x64, 0x5f, 0x64, 0x65, 0x76, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x73, 0x74, 0x64, 0x44, 0x65, 0x76, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x29, 0x0a, 0x10,
+	0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65,
+	0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x22, 0xb8, 0x01, 0x0a, 0x0c, 0x43, 0x6f, 0x73, 0x74,
+	0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x66,
+	0x66, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x74, 0x72, 0x61, 0x66, 0x66,
+	0x69, 0x63, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x73, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x03, 0x75, 0x73, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x63, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x02, 0x63, 0x63, 0x12, 0x1c, 0x0a, 0x0a, 0x75, 0x73, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x5f,
+	0x6d, 0x62, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x75, 0x73, 0x64, 0x50, 0x65, 0x72,
+	0x4d, 0x62, 0x12, 0x1c, 0x0a, 0x0a, 0x75, 0x73, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x63, 0x63,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x75, 0x73, 0x64, 0x50, 0x65, 0x72, 0x43, 0x63,
+	0x12, 0x30, 0x0a, 0x14, 0x72, 0x61, 0x74, 0x65, 0x73, 0x5f, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12,
+	0x72, 0x61, 0x74, 0x65, 0x73, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x46, 0x72,
+	0x6f, 0x6d, 0x22, 0xdf, 0x01, 0x0a, 0x0a, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x73,
+	0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x70,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x74,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x68,
+	0x6f, 0x69, 0x63, 0x65, 0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x44, 0x4c, 0x43, 0x2d, 0x6c, 0x69, 0x6e, 0x6b, 0x2f, 0x63, 0x61, 0x6e, 0x74,
+	0x63, 0x6f, 0x73, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x61, 0x6e, 0x74, 0x63,
+	0x6f, 0x73, 0x74, 0x2f, 0x76, 0x31, 0x3b, 0x63, 0x61, 0x6e, 0x74, 0x63, 0x6f, 0x73, 0x74, 0x76,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_cantcost_v1_export_proto_rawDescOnce sync.Once
+	file_cantcost_v1_export_proto_rawDescData []byte
+)
+
+func file_cantcost_v1_export_proto_rawDescGZIP() []byte {
+	file_cantcost_v1_export_proto_rawDescOnce.Do(func() {
+		file_cantcost_v1_export_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_cantcost_v1_export_proto_rawDesc), len(file_cantcost_v1_export_proto_rawDesc)))
+	})
+	return file_cantcost_v1_export_proto_rawDescData
+}
+
+var file_cantcost_v1_export_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_cantcost_v1_export_proto_goTypes = []any{
+	(*ExportRequest)(nil),       // 0: cantcost.v1.ExportRequest
+	(*Line)(nil),                // 1: cantcost.v1.Line
+	(*Recipient)(nil),           // 2: cantcost.v1.Recipient
+	(*EnvelopeCostDetails)(nil), // 3: cantcost.v1.EnvelopeCostDetails
+	(*EventCostDetails)(nil),    // 4: cantcost.v1.EventCostDetails
+	(*TrafficState)(nil),        // 5: cantcost.v1.TrafficState
+	(*TrafficReceipt)(nil),      // 6: cantcost.v1.TrafficReceipt
+	(*TrafficPurchased)(nil),    // 7: cantcost.v1.TrafficPurchased
+	(*TrafficRejection)(nil),    // 8: cantcost.v1.TrafficRejection
+	(*TraceSummary)(nil),        // 9: cantcost.v1.TraceSummary
+	(*CounterpartyCost)(nil),    // 10: cantcost.v1.CounterpartyCost
+	(*Rollup)(nil),              // 11: cantcost.v1.Rollup
+	(*Anomaly)(nil),             // 12: cantcost.v1.Anomaly
+	(*CostEstimate)(nil),        // 13: cantcost.v1.CostEstimate
+	(*Submission)(nil),          // 14: cantcost.v1.Submission
+	nil,                         // 15: cantcost.v1.EventCostDetails.GroupToMembersSizeEntry
+}
+var file_cantcost_v1_export_proto_depIdxs = []int32{
+	1,  // 0: cantcost.v1.ExportRequest.lines:type_name -> cantcost.v1.Line
+	4,  // 1: cantcost.v1.Line.cost_details:type_name -> cantcost.v1.EventCostDetails
+	5,  // 2: cantcost.v1.Line.traffic_state:type_name -> cantcost.v1.TrafficState
+	6,  // 3: cantcost.v1.Line.traffic_receipt:type_name -> cantcost.v1.TrafficReceipt
+	7,  // 4: cantcost.v1.Line.traffic_purchased:type_name -> cantcost.v1.TrafficPurchased
+	8,  // 5: cantcost.v1.Line.traffic_rejection:type_name -> cantcost.v1.TrafficRejection
+	9,  // 6: cantcost.v1.Line.trace_summary:type_name -> cantcost.v1.TraceSummary
+	10, // 7: cantcost.v1.Line.counterparty_cost:type_name -> cantcost.v1.CounterpartyCost
+	11, // 8: cantcost.v1.Line.rollup:type_name -> cantcost.v1.Rollup
+	12, // 9: cantcost.v1.Line.anomaly:type_name -> cantcost.v1.Anomaly
+	13, // 10: cantcost.v1.Line.cost_estimate:type_name -> cantcost.v1.CostEstimate
+	14, // 11: cantcost.v1.Line.submission:type_name -> cantcost.v1.Submission
+	2,  // 12: cantcost.v1.EnvelopeCostDetails.recipients:type_name -> cantcost.v1.Recipient
+	15, // 13: cantcost.v1.EventCostDetails.group_to_members_size:type_name -> cantcost.v1.EventCostDetails.GroupToMembersSizeEntry
+	3,  // 14: cantcost.v1.EventCostDetails.envelopes_cost:type_name -> cantcost.v1.EnvelopeCostDetails
+	15, // [15:15] is the sub-list for method output_type
+	15, // [15:15] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_cantcost_v1_export_proto_init() }
+func file_cantcost_v1_export_proto_init() {
+	if File_cantcost_v1_export_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cantcost_v1_export_proto_rawDesc), len(file_cantcost_v1_export_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_cantcost_v1_export_proto_goTypes,
+		DependencyIndexes: file_cantcost_v1_export_proto_depIdxs,
+		MessageInfos:      file_cantcost_v1_export_proto_msgTypes,
+	}.Build()
+	File_cantcost_v1_export_proto = out.File
+	file_cantcost_v1_export_proto_goTypes = nil
+	file_cantcost_v1_export_proto_depIdxs = nil
+}
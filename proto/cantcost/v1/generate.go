@@ -0,0 +1,3 @@
+package cantcostv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative cantcost/v1/export.proto